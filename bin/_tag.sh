@@ -6,6 +6,10 @@ git_sha_head() {
     git rev-parse --short=8 HEAD
 }
 
+git_sha_full() {
+    git rev-parse HEAD
+}
+
 clean_head() {
     [ -n "${CI_FORCE_CLEAN:-}" ] || git diff-index --quiet HEAD --
 }
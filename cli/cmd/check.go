@@ -25,10 +25,16 @@ type checkOptions struct {
 	preInstallOnly     bool
 	dataPlaneOnly      bool
 	wait               time.Duration
+	retryWindow        time.Duration
 	namespace          string
 	cniEnabled         bool
 	output             string
 	cliVersionOverride string
+	failOnWarning      bool
+	skipVersionChecks  bool
+	versionCacheTTL    time.Duration
+	noVersionCache     bool
+	categories         []string
 }
 
 func newCheckOptions() *checkOptions {
@@ -37,10 +43,16 @@ func newCheckOptions() *checkOptions {
 		preInstallOnly:     false,
 		dataPlaneOnly:      false,
 		wait:               300 * time.Second,
+		retryWindow:        5 * time.Second,
 		namespace:          "",
 		cniEnabled:         false,
 		output:             tableOutput,
 		cliVersionOverride: "",
+		failOnWarning:      false,
+		skipVersionChecks:  false,
+		versionCacheTTL:    version.CacheTTL,
+		noVersionCache:     false,
+		categories:         []string{},
 	}
 }
 
@@ -64,6 +76,12 @@ func (options *checkOptions) checkFlagSet() *pflag.FlagSet {
 	flags.StringVar(&options.cliVersionOverride, "cli-version-override", "", "Used to override the version of the cli (mostly for testing)")
 	flags.StringVarP(&options.output, "output", "o", options.output, "Output format. One of: basic, json, short")
 	flags.DurationVar(&options.wait, "wait", options.wait, "Maximum allowed time for all tests to pass")
+	flags.DurationVar(&options.retryWindow, "retry-window", options.retryWindow, "Time to wait before retrying a failing check, doubled after each attempt up to 20s, until --wait elapses")
+	flags.BoolVar(&options.skipVersionChecks, "skip-version-checks", options.skipVersionChecks, "Skip the checks that compare the CLI, control plane and proxy versions against the latest release, which require access to the public version check endpoint")
+	flags.DurationVar(&options.versionCacheTTL, "version-cache-ttl", options.versionCacheTTL, "How long the latest Linkerd versions are cached on disk between runs")
+	flags.BoolVar(&options.noVersionCache, "no-version-cache", options.noVersionCache, "Always fetch the latest Linkerd versions instead of using the cached ones")
+	flags.BoolVar(&options.failOnWarning, "fail-on-warning", options.failOnWarning, "Exit with a non-zero exit code if any check results in a warning")
+	flags.StringArrayVar(&options.categories, "category", options.categories, "Only run the checks in this category (e.g. \"linkerd-version\"); can be repeated. The kubernetes-api and linkerd-config categories always run, as the others depend on them, and extension checks are skipped")
 
 	return flags
 }
@@ -75,6 +93,15 @@ func (options *checkOptions) validate() error {
 	if !options.preInstallOnly && options.cniEnabled {
 		return errors.New("--linkerd-cni-enabled can only be used with --pre")
 	}
+	if options.skipVersionChecks && options.versionOverride != "" {
+		return errors.New("--skip-version-checks and --expected-version flags are mutually exclusive")
+	}
+	if options.retryWindow <= 0 {
+		return errors.New("--retry-window must be positive")
+	}
+	if options.versionCacheTTL < 0 {
+		return errors.New("--version-cache-ttl must not be negative")
+	}
 	if options.output != tableOutput && options.output != jsonOutput && options.output != shortOutput {
 		return fmt.Errorf("Invalid output type '%s'. Supported output types are: %s, %s, %s", options.output, jsonOutput, tableOutput, shortOutput)
 	}
@@ -156,6 +183,11 @@ func configureAndRunChecks(cmd *cobra.Command, wout io.Writer, werr io.Writer, s
 		version.Version = options.cliVersionOverride
 	}
 
+	version.CacheTTL = options.versionCacheTTL
+	if options.noVersionCache {
+		version.CacheTTL = 0
+	}
+
 	checks := []healthcheck.CategoryID{
 		healthcheck.KubernetesAPIChecks,
 		healthcheck.KubernetesVersionChecks,
@@ -196,6 +228,11 @@ func configureAndRunChecks(cmd *cobra.Command, wout io.Writer, werr io.Writer, s
 		}
 	}
 
+	checks, err = filterCategories(checks, options.categories)
+	if err != nil {
+		return fmt.Errorf("Validation error when executing check command: %v", err)
+	}
+
 	hc := healthcheck.NewHealthChecker(checks, &healthcheck.Options{
 		ControlPlaneNamespace: controlPlaneNamespace,
 		CNINamespace:          cniNamespace,
@@ -206,7 +243,9 @@ func configureAndRunChecks(cmd *cobra.Command, wout io.Writer, werr io.Writer, s
 		ImpersonateGroup:      impersonateGroup,
 		APIAddr:               apiAddr,
 		VersionOverride:       options.versionOverride,
+		SkipVersionChecks:     options.skipVersionChecks,
 		RetryDeadline:         time.Now().Add(options.wait),
+		RetryWindow:           options.retryWindow,
 		CNIEnabled:            options.cniEnabled,
 		InstallManifest:       installManifest,
 	})
@@ -215,13 +254,21 @@ func configureAndRunChecks(cmd *cobra.Command, wout io.Writer, werr io.Writer, s
 		healthcheck.PrintCoreChecksHeader(wout)
 	}
 
-	success := healthcheck.RunChecks(wout, werr, hc, options.output)
+	var runner healthcheck.Runner = hc
+	if options.failOnWarning {
+		runner = healthcheck.FailOnWarning(hc)
+	}
 
-	extensionSuccess, err := runExtensionChecks(cmd, wout, werr, options)
-	if err != nil {
-		err = fmt.Errorf("failed to run extensions checks: %s", err)
-		fmt.Fprintln(werr, err)
-		os.Exit(1)
+	success := healthcheck.RunChecks(wout, werr, runner, options.output)
+
+	extensionSuccess := true
+	if len(options.categories) == 0 {
+		extensionSuccess, err = runExtensionChecks(cmd, wout, werr, options)
+		if err != nil {
+			err = fmt.Errorf("failed to run extensions checks: %s", err)
+			fmt.Fprintln(werr, err)
+			os.Exit(1)
+		}
 	}
 
 	if !success || !extensionSuccess {
@@ -231,6 +278,46 @@ func configureAndRunChecks(cmd *cobra.Command, wout io.Writer, werr io.Writer, s
 	return nil
 }
 
+// filterCategories narrows checks down to the given categories, keeping their
+// original order. The categories initializing the Kubernetes client and
+// loading the Linkerd configuration are always kept, as the others depend on
+// them. An error is returned for categories not part of checks.
+func filterCategories(checks []healthcheck.CategoryID, categories []string) ([]healthcheck.CategoryID, error) {
+	if len(categories) == 0 {
+		return checks, nil
+	}
+
+	selected := map[healthcheck.CategoryID]bool{
+		healthcheck.KubernetesAPIChecks: true,
+		healthcheck.LinkerdConfigChecks: true,
+	}
+	for _, category := range categories {
+		found := false
+		for _, check := range checks {
+			if check == healthcheck.CategoryID(category) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			available := make([]string, len(checks))
+			for i, check := range checks {
+				available[i] = string(check)
+			}
+			return nil, fmt.Errorf("unknown check category \"%s\"; available categories: %s", category, strings.Join(available, ", "))
+		}
+		selected[healthcheck.CategoryID(category)] = true
+	}
+
+	filtered := []healthcheck.CategoryID{}
+	for _, check := range checks {
+		if selected[check] {
+			filtered = append(filtered, check)
+		}
+	}
+	return filtered, nil
+}
+
 func runExtensionChecks(cmd *cobra.Command, wout io.Writer, werr io.Writer, opts *checkOptions) (bool, error) {
 	kubeAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup, 0)
 	if err != nil {
@@ -253,7 +340,7 @@ func runExtensionChecks(cmd *cobra.Command, wout io.Writer, werr io.Writer, opts
 		nsLabels[i] = ns.Labels[k8s.LinkerdExtensionLabel]
 	}
 
-	extensionSuccess := healthcheck.RunExtensionsChecks(wout, werr, nsLabels, getExtensionCheckFlags(cmd.Flags()), opts.output)
+	extensionSuccess := healthcheck.RunExtensionsChecks(wout, werr, nsLabels, getExtensionCheckFlags(cmd.Flags()), opts.output, opts.failOnWarning)
 	return extensionSuccess, nil
 }
 
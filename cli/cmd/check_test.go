@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
@@ -79,3 +81,93 @@ func TestCheckStatus(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckFailOnWarning(t *testing.T) {
+	newWarningHealthChecker := func() *healthcheck.HealthChecker {
+		hc := healthcheck.NewHealthChecker(
+			[]healthcheck.CategoryID{},
+			&healthcheck.Options{},
+		)
+		hc.AppendCategories(healthcheck.NewCategory("category", []healthcheck.Checker{
+			*healthcheck.NewChecker("check1").
+				WithCheck(func(context.Context) error {
+					return nil
+				}),
+			*healthcheck.NewChecker("check2").
+				Warning().
+				WithCheck(func(context.Context) error {
+					return fmt.Errorf("This should contain instructions for warning")
+				}),
+		},
+			true,
+		))
+		return hc
+	}
+
+	t.Run("Succeeds on warnings by default", func(t *testing.T) {
+		output := bytes.NewBufferString("")
+		if !healthcheck.RunChecks(output, stderr, newWarningHealthChecker(), tableOutput) {
+			t.Fatalf("Expected checks to succeed, got:\n%s", output)
+		}
+	})
+
+	t.Run("Fails on warnings with FailOnWarning", func(t *testing.T) {
+		output := bytes.NewBufferString("")
+		if healthcheck.RunChecks(output, stderr, healthcheck.FailOnWarning(newWarningHealthChecker()), tableOutput) {
+			t.Fatalf("Expected checks to fail, got:\n%s", output)
+		}
+	})
+}
+
+func TestFilterCategories(t *testing.T) {
+	checks := []healthcheck.CategoryID{
+		healthcheck.KubernetesAPIChecks,
+		healthcheck.KubernetesVersionChecks,
+		healthcheck.LinkerdVersionChecks,
+		healthcheck.LinkerdConfigChecks,
+		healthcheck.LinkerdControlPlaneVersionChecks,
+	}
+
+	testCases := []struct {
+		categories  []string
+		expected    []healthcheck.CategoryID
+		expectedErr string
+	}{
+		{
+			categories: []string{},
+			expected:   checks,
+		},
+		{
+			categories: []string{"control-plane-version", "linkerd-version"},
+			expected: []healthcheck.CategoryID{
+				healthcheck.KubernetesAPIChecks,
+				healthcheck.LinkerdVersionChecks,
+				healthcheck.LinkerdConfigChecks,
+				healthcheck.LinkerdControlPlaneVersionChecks,
+			},
+		},
+		{
+			categories:  []string{"linkerd-data-plane"},
+			expectedErr: "unknown check category \"linkerd-data-plane\"; available categories: kubernetes-api, kubernetes-version, linkerd-version, linkerd-config, control-plane-version",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(strings.Join(tc.categories, ","), func(t *testing.T) {
+			filtered, err := filterCategories(checks, tc.categories)
+			if tc.expectedErr != "" {
+				if err == nil || err.Error() != tc.expectedErr {
+					t.Fatalf("Expected error %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(filtered, tc.expected) {
+				t.Fatalf("Expected categories %v, got %v", tc.expected, filtered)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/linkerd/linkerd2/pkg/charts/linkerd2"
+	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+type configOptions struct {
+	output string
+	proxy  bool
+}
+
+func newConfigOptions() *configOptions {
+	return &configOptions{
+		output: yamlOutput,
+		proxy:  false,
+	}
+}
+
+func (options *configOptions) validate() error {
+	if options.output != yamlOutput && options.output != jsonOutput {
+		return fmt.Errorf("Invalid output type '%s'. Supported output types are: %s, %s", options.output, yamlOutput, jsonOutput)
+	}
+	return nil
+}
+
+func newCmdConfig() *cobra.Command {
+	options := newConfigOptions()
+
+	cmd := &cobra.Command{
+		Use:   "config [flags]",
+		Args:  cobra.NoArgs,
+		Short: "Output the configuration of the current Linkerd installation",
+		Long: `Output the configuration of the current Linkerd installation.
+
+The configuration is read from the linkerd-config ConfigMap, and holds the
+values the control plane was installed or last upgraded with, merged with the
+chart defaults.`,
+		Example: `  # Output the configuration of the Linkerd installation
+  linkerd config
+
+  # Output the proxy configuration only, as JSON
+  linkerd config --proxy -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.validate(); err != nil {
+				return err
+			}
+
+			k8sAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup, 0)
+			if err != nil {
+				return err
+			}
+
+			_, values, err := healthcheck.FetchCurrentConfiguration(cmd.Context(), k8sAPI, controlPlaneNamespace)
+			if err != nil {
+				return fmt.Errorf("failed to fetch the Linkerd configuration: %s", err)
+			}
+			if values == nil {
+				return errors.New("the linkerd-config ConfigMap doesn't hold any configuration values")
+			}
+
+			return renderConfig(stdout, values, options)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.output, "output", "o", options.output, fmt.Sprintf("Output format. One of: %s, %s", yamlOutput, jsonOutput))
+	cmd.Flags().BoolVar(&options.proxy, "proxy", options.proxy, "Only output the proxy configuration")
+
+	return cmd
+}
+
+func renderConfig(w io.Writer, values *linkerd2.Values, options *configOptions) error {
+	var config interface{} = values
+	if options.proxy {
+		config = values.Proxy
+	}
+
+	var out []byte
+	var err error
+	if options.output == jsonOutput {
+		out, err = json.MarshalIndent(config, "", "  ")
+		out = append(out, '\n')
+	} else {
+		out, err = yaml.Marshal(config)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(out)
+	return err
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/charts/linkerd2"
+)
+
+func TestRenderConfig(t *testing.T) {
+	values, err := linkerd2.NewValues()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		options        *configOptions
+		goldenFileName string
+	}{
+		{&configOptions{output: yamlOutput}, "config_default.golden"},
+		{&configOptions{output: jsonOutput, proxy: true}, "config_proxy_json.golden"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.goldenFileName, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := renderConfig(&buf, values, tc.options); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			testDataDiffer.DiffTestdata(t, tc.goldenFileName, buf.String())
+		})
+	}
+}
+
+func TestConfigOptionsValidate(t *testing.T) {
+	options := newConfigOptions()
+	options.output = tableOutput
+	err := options.validate()
+	expected := "Invalid output type 'table'. Supported output types are: yaml, json"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected error %q, got %v", expected, err)
+	}
+}
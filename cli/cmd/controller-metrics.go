@@ -3,6 +3,7 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/linkerd/linkerd2/pkg/k8s"
@@ -13,16 +14,32 @@ import (
 // ControllerMetricsOptions holds values for command line flags that apply to the controller-metrics
 // command.
 type ControllerMetricsOptions struct {
-	wait time.Duration
+	wait        time.Duration
+	concurrency int
+	retries     int
+	timeout     time.Duration
+	path        string
+	node        string
+	scheme      string
+	insecure    bool
 }
 
 // newControllerMetricsOptions initializes controller-metrics options setting
-// the max wait time duration as 30 seconds to fetch controller-metrics
+// the max wait time duration as 30 seconds to fetch controller-metrics, the
+// number of pods to fetch metrics from at the same time, and the number of
+// retries for failed requests
 //
 // This option may be overridden on the CLI at run-time
 func newControllerMetricsOptions() *ControllerMetricsOptions {
 	return &ControllerMetricsOptions{
-		wait: 30 * time.Second,
+		wait:        defaultMetricsWait,
+		concurrency: defaultMetricsConcurrency,
+		retries:     defaultMetricsRetries,
+		timeout:     defaultMetricsRequestTimeout,
+		path:        defaultMetricsPath,
+		node:        "",
+		scheme:      defaultMetricsScheme,
+		insecure:    false,
 	}
 }
 
@@ -37,9 +54,20 @@ func newCmdControllerMetrics() *cobra.Command {
 		Long: `Fetch metrics directly from Linkerd control plane containers.
 
   This command initiates port-forward to each control plane process, and
-  queries the /metrics endpoint on them.`,
+  queries the /metrics endpoint (or the one set with --path) on them.`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			scrape := scrapeOptions{
+				retries:            options.retries,
+				timeout:            options.timeout,
+				path:               options.path,
+				scheme:             options.scheme,
+				insecureSkipVerify: options.insecure,
+			}
+			if err := scrape.validate(); err != nil {
+				return err
+			}
+
 			k8sAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup, 0)
 			if err != nil {
 				return err
@@ -50,7 +78,15 @@ func newCmdControllerMetrics() *cobra.Command {
 				return err
 			}
 
-			results := getMetrics(k8sAPI, pods.Items, adminHTTPPortName, options.wait, verbose)
+			podsOnNode, err := filterPodsByNode(pods.Items, options.node)
+			if err != nil {
+				return err
+			}
+
+			results, summary := getMetrics(k8sAPI, podsOnNode, adminHTTPPortName, options.wait, options.concurrency, verbose, scrape)
+			if summary.completed < summary.total {
+				fmt.Fprintf(os.Stderr, "Fetched metrics from %d of %d pods before timing out\n", summary.completed, summary.total)
+			}
 
 			var buf bytes.Buffer
 			for i, result := range results {
@@ -70,6 +106,13 @@ func newCmdControllerMetrics() *cobra.Command {
 	}
 
 	cmd.Flags().DurationVarP(&options.wait, "wait", "w", options.wait, "Time allowed to fetch diagnostics")
+	cmd.Flags().IntVar(&options.concurrency, "concurrency", options.concurrency, "Maximum number of pods to fetch metrics from at the same time")
+	cmd.Flags().IntVar(&options.retries, "retries", options.retries, "Number of times a failed metrics request is retried")
+	cmd.Flags().DurationVar(&options.timeout, "request-timeout", options.timeout, "Time allowed for a single metrics request (0 means no timeout)")
+	cmd.Flags().StringVar(&options.path, "path", options.path, "URL path the metrics are exposed at")
+	cmd.Flags().StringVar(&options.scheme, "scheme", options.scheme, "URL scheme the metrics are served over; one of: \"http\" or \"https\"")
+	cmd.Flags().BoolVar(&options.insecure, "insecure-skip-verify", options.insecure, "If present, don't verify the certificate the metrics are served with over https")
+	cmd.Flags().StringVar(&options.node, "node", options.node, "If present, only fetch metrics from the control plane pods scheduled on this node")
 
 	return cmd
 }
@@ -24,7 +24,10 @@ This command provides subcommands to diagnose the functionality of Linkerd.`,
 
   # Get metrics from the web deployment in the emojivoto namespace.
   linkerd diagnostics proxy-metrics -n emojivoto deploy/web
- 
+
+  # Stream the proxy logs of the web deployment in the emojivoto namespace.
+  linkerd diagnostics proxy-logs -n emojivoto deploy/web
+
   # Get the endpoints for authorities in Linkerd's control-plane itself
   linkerd diagnostics endpoints web.linkerd-viz.svc.cluster.local:8084
   `,
@@ -33,6 +36,7 @@ This command provides subcommands to diagnose the functionality of Linkerd.`,
 	diagnosticsCmd.AddCommand(newCmdControllerMetrics())
 	diagnosticsCmd.AddCommand(newCmdEndpoints())
 	diagnosticsCmd.AddCommand(newCmdMetrics())
+	diagnosticsCmd.AddCommand(newCmdProxyLogs())
 
 	return diagnosticsCmd
 }
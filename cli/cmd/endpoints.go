@@ -25,7 +25,8 @@ import (
 )
 
 type endpointsOptions struct {
-	outputFormat string
+	outputFormat   string
+	maxMessageSize int
 }
 
 type (
@@ -47,16 +48,21 @@ const (
 // validate performs all validation on the command-line options.
 // It returns the first error encountered, or `nil` if the options are valid.
 func (o *endpointsOptions) validate() error {
-	if o.outputFormat == tableOutput || o.outputFormat == jsonOutput {
-		return nil
+	if o.outputFormat != tableOutput && o.outputFormat != jsonOutput {
+		return fmt.Errorf("--output currently only supports %s and %s", tableOutput, jsonOutput)
 	}
 
-	return fmt.Errorf("--output currently only supports %s and %s", tableOutput, jsonOutput)
+	if o.maxMessageSize <= 0 {
+		return errors.New("--max-message-size must be positive")
+	}
+
+	return nil
 }
 
 func newEndpointsOptions() *endpointsOptions {
 	return &endpointsOptions{
-		outputFormat: tableOutput,
+		outputFormat:   tableOutput,
+		maxMessageSize: destination.DefaultMaxMessageSize,
 	}
 }
 
@@ -95,7 +101,7 @@ destination.`,
 				return err
 			}
 
-			client, conn, err := destination.NewExternalClient(cmd.Context(), controlPlaneNamespace, k8sAPI)
+			client, conn, err := destination.NewExternalClientWithMaxMessageSize(cmd.Context(), controlPlaneNamespace, k8sAPI, options.maxMessageSize)
 			if err != nil {
 				fmt.Fprint(os.Stderr, fmt.Errorf("Error creating destination client: %s", err))
 				os.Exit(1)
@@ -116,6 +122,7 @@ destination.`,
 	}
 
 	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, fmt.Sprintf("Output format; one of: \"%s\" or \"%s\"", tableOutput, jsonOutput))
+	cmd.PersistentFlags().IntVar(&options.maxMessageSize, "max-message-size", options.maxMessageSize, "Maximum size in bytes of the messages received from the Destination API (defaults to 4MB)")
 
 	pkgcmd.ConfigureOutputFlagCompletion(cmd)
 
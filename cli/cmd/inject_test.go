@@ -14,6 +14,7 @@ import (
 	"github.com/linkerd/linkerd2/pkg/charts/linkerd2"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/testutil"
+	corev1 "k8s.io/api/core/v1"
 )
 
 type testCase struct {
@@ -98,6 +99,11 @@ func TestUninjectAndInject(t *testing.T) {
 	ingressConfig := defaultConfig()
 	ingressConfig.Proxy.IsIngress = true
 
+	extraEnvConfig := defaultConfig()
+	extraEnvConfig.Proxy.ExtraEnv = []corev1.EnvVar{
+		{Name: "LINKERD2_PROXY_FEATURE", Value: "true"},
+	}
+
 	proxyIgnorePortsConfig := defaultConfig()
 	proxyIgnorePortsConfig.ProxyInit.IgnoreInboundPorts = "22,8100-8102"
 	proxyIgnorePortsConfig.ProxyInit.IgnoreOutboundPorts = "5432"
@@ -309,6 +315,13 @@ func TestUninjectAndInject(t *testing.T) {
 			injectProxy:      true,
 			testInjectConfig: ingressConfig,
 		},
+		{
+			inputFileName:    "inject_emojivoto_pod.input.yml",
+			goldenFileName:   "inject_emojivoto_pod_extra_env.golden.yml",
+			reportFileName:   "inject_emojivoto_pod.report",
+			injectProxy:      true,
+			testInjectConfig: extraEnvConfig,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/linkerd/linkerd2/cli/flag"
@@ -191,6 +193,43 @@ func TestRender(t *testing.T) {
 	withCustomDestinationGetNetsValues.ClusterNetworks = "10.0.0.0/8,100.64.0.0/10,172.0.0.0/8"
 	addFakeTLSSecrets(withCustomDestinationGetNetsValues)
 
+	withComponentProxyResourcesValues, err := testInstallOptions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	withComponentProxyResourcesValues.Proxy.Resources.CPU.Request = "100m"
+	withComponentProxyResourcesValues.Proxy.Resources.Memory.Request = "20Mi"
+	withComponentProxyResourcesValues.DestinationProxyResources = &charts.Resources{
+		Memory: charts.Constraints{
+			Limit:   "1Gi",
+			Request: "512Mi",
+		},
+	}
+	addFakeTLSSecrets(withComponentProxyResourcesValues)
+
+	withTolerationsValues, err := testInstallOptions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	withTolerationsValues.Tolerations = []corev1.Toleration{
+		{
+			Key:      "dedicated",
+			Operator: corev1.TolerationOpEqual,
+			Value:    "linkerd",
+			Effect:   corev1.TaintEffectNoSchedule,
+		},
+	}
+	addFakeTLSSecrets(withTolerationsValues)
+
+	withImagePullSecretsValues, err := testInstallOptions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	withImagePullSecretsValues.ImagePullSecrets = []corev1.LocalObjectReference{
+		{Name: "my-private-docker-registry-login-secret"},
+	}
+	addFakeTLSSecrets(withImagePullSecretsValues)
+
 	testCases := []struct {
 		values         *charts.Values
 		goldenFileName string
@@ -206,6 +245,9 @@ func TestRender(t *testing.T) {
 		{withControlPlaneTracingValues, "install_controlplane_tracing_output.golden", values.Options{}},
 		{withCustomRegistryValues, "install_custom_registry.golden", values.Options{}},
 		{withCustomDestinationGetNetsValues, "install_default_override_dst_get_nets.golden", values.Options{}},
+		{withComponentProxyResourcesValues, "install_component_proxy_resources.golden", values.Options{}},
+		{withTolerationsValues, "install_tolerations.golden", values.Options{}},
+		{withImagePullSecretsValues, "install_image_pull_secrets.golden", values.Options{}},
 		{defaultValues, "install_custom_domain.golden", values.Options{Values: []string{"namespace=l5d"}}},
 		{defaultValues, "install_values_file.golden", values.Options{ValueFiles: []string{filepath.Join("testdata", "install_config.yaml")}}},
 	}
@@ -222,6 +264,147 @@ func TestRender(t *testing.T) {
 	}
 }
 
+func TestRenderPriorityClassName(t *testing.T) {
+	priorityClassValues, err := testInstallOptions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+	priorityClassValues.PriorityClassName = "system-cluster-critical"
+	addFakeTLSSecrets(priorityClassValues)
+
+	var buf bytes.Buffer
+	if err := render(&buf, priorityClassValues, "", values.Options{}); err != nil {
+		t.Fatalf("Failed to render templates: %v", err)
+	}
+
+	// the destination, identity, proxy-injector and heartbeat pod specs, plus
+	// the values stored in the linkerd-config ConfigMap
+	expected := 5
+	if count := strings.Count(buf.String(), "priorityClassName: system-cluster-critical\n"); count != expected {
+		t.Fatalf("Expected the priority class to be rendered %d times, found %d", expected, count)
+	}
+}
+
+func TestRenderProxyInjectorEndpoints(t *testing.T) {
+	testCases := []struct {
+		enabled bool
+	}{
+		{false},
+		{true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(fmt.Sprintf("enabled=%t", tc.enabled), func(t *testing.T) {
+			failurePolicyValues, err := testInstallOptions()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			failurePolicyValues.ProxyInjector.EnableFailurePolicyEndpoint = tc.enabled
+			failurePolicyValues.ProxyInjector.EnableDryRunEndpoint = tc.enabled
+			addFakeTLSSecrets(failurePolicyValues)
+
+			var buf bytes.Buffer
+			if err := render(&buf, failurePolicyValues, "", values.Options{}); err != nil {
+				t.Fatalf("Failed to render templates: %v", err)
+			}
+
+			// the proxy injector is only allowed to patch its webhook
+			// configuration when it serves the failure policy endpoint
+			for _, rendered := range []string{
+				"- -failure-policy-addr=localhost:9997\n",
+				"- -dry-run-addr=localhost:9998\n",
+				"resources: [\"mutatingwebhookconfigurations\"]\n",
+			} {
+				if strings.Contains(buf.String(), rendered) != tc.enabled {
+					t.Fatalf("Expected %q to be rendered: %t", rendered, tc.enabled)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderTraceSamplingEndpoint(t *testing.T) {
+	testCases := []struct {
+		tracing  bool
+		enabled  bool
+		rendered bool
+	}{
+		{false, false, false},
+		{false, true, false},
+		{true, false, false},
+		{true, true, true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(fmt.Sprintf("tracing=%t/enabled=%t", tc.tracing, tc.enabled), func(t *testing.T) {
+			samplingValues, err := testInstallOptions()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			samplingValues.ControlPlaneTracing = tc.tracing
+			samplingValues.ControlPlaneTracingSamplingEndpoint = tc.enabled
+			addFakeTLSSecrets(samplingValues)
+
+			var buf bytes.Buffer
+			if err := render(&buf, samplingValues, "", values.Options{}); err != nil {
+				t.Fatalf("Failed to render templates: %v", err)
+			}
+
+			// the endpoint is only served when there's a collector to
+			// sample traces for
+			rendered := "- -trace-sampling-addr=localhost:9997\n"
+			if strings.Contains(buf.String(), rendered) != tc.rendered {
+				t.Fatalf("Expected %q to be rendered: %t", rendered, tc.rendered)
+			}
+		})
+	}
+}
+
+func TestNodeSelectorFlag(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected map[string]string
+	}{
+		{
+			"linkerd=true",
+			map[string]string{"beta.kubernetes.io/os": "linux", "linkerd": "true"},
+		},
+		{
+			"linkerd=true,pool=control-plane",
+			map[string]string{"beta.kubernetes.io/os": "linux", "linkerd": "true", "pool": "control-plane"},
+		},
+		{
+			"beta.kubernetes.io/os=windows",
+			map[string]string{"beta.kubernetes.io/os": "windows"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.value, func(t *testing.T) {
+			values, err := testInstallOptions()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			flags, flagSet, err := makeInstallUpgradeFlags(values)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			if err := flagSet.Set("node-selector", tc.value); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			if err := flag.ApplySetFlags(values, flags); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			if !reflect.DeepEqual(values.NodeSelector, tc.expected) {
+				t.Fatalf("Expected node selector %v, got %v", tc.expected, values.NodeSelector)
+			}
+		})
+	}
+}
+
 func TestValidateAndBuild_Errors(t *testing.T) {
 	t.Run("Fails validation for invalid ignoreInboundPorts", func(t *testing.T) {
 		values, err := testInstallOptions()
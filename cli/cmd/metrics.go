@@ -5,6 +5,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"text/tabwriter"
 	"time"
 
 	"github.com/linkerd/linkerd2/controller/api/util"
@@ -19,13 +21,31 @@ import (
 )
 
 type metricsOptions struct {
-	namespace string
-	pod       string
+	namespace   string
+	pod         string
+	wait        time.Duration
+	concurrency int
+	retries     int
+	timeout     time.Duration
+	path        string
+	node        string
+	scheme      string
+	insecure    bool
+	summary     bool
 }
 
 func newMetricsOptions() *metricsOptions {
 	return &metricsOptions{
-		pod: "",
+		pod:         "",
+		wait:        defaultMetricsWait,
+		concurrency: defaultMetricsConcurrency,
+		retries:     defaultMetricsRetries,
+		timeout:     defaultMetricsRequestTimeout,
+		path:        defaultMetricsPath,
+		node:        "",
+		scheme:      defaultMetricsScheme,
+		insecure:    false,
+		summary:     false,
 	}
 }
 
@@ -38,7 +58,8 @@ func newCmdMetrics() *cobra.Command {
 		Long: `Fetch metrics directly from Linkerd proxies.
 
   This command initiates a port-forward to a given pod or set of pods, and
-  queries the /metrics endpoint on the Linkerd proxies.
+  queries the /metrics endpoint (or the one set with --path) on the Linkerd
+  proxies.
 
   The RESOURCE argument specifies the target resource to query metrics for:
   (TYPE/NAME)
@@ -67,6 +88,12 @@ func newCmdMetrics() *cobra.Command {
   # Get metrics from the web deployment in the emojivoto namespace.
   linkerd diagnostics proxy-metrics -n emojivoto deploy/web
 
+  # Get metrics from the pods of the web deployment running on node-1.
+  linkerd diagnostics proxy-metrics -n emojivoto deploy/web --node node-1
+
+  # Show the total inbound and outbound requests of each pod of the web deployment.
+  linkerd diagnostics proxy-metrics -n emojivoto deploy/web --summary
+
   # Get metrics from the linkerd-destination pod in the linkerd namespace.
   linkerd diagnostics proxy-metrics -n linkerd $(
     kubectl --namespace linkerd get pod \
@@ -75,6 +102,17 @@ func newCmdMetrics() *cobra.Command {
   )`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			scrape := scrapeOptions{
+				retries:            options.retries,
+				timeout:            options.timeout,
+				path:               options.path,
+				scheme:             options.scheme,
+				insecureSkipVerify: options.insecure,
+			}
+			if err := scrape.validate(); err != nil {
+				return err
+			}
+
 			if options.namespace == "" {
 				options.namespace = pkgcmd.GetDefaultNamespace(kubeconfigPath, kubeContext)
 			}
@@ -88,7 +126,20 @@ func newCmdMetrics() *cobra.Command {
 				return err
 			}
 
-			results := getMetrics(k8sAPI, pods, k8s.ProxyAdminPortName, 30*time.Second, verbose)
+			pods, err = filterPodsByNode(pods, options.node)
+			if err != nil {
+				return err
+			}
+
+			results, summary := getMetrics(k8sAPI, pods, k8s.ProxyAdminPortName, options.wait, options.concurrency, verbose, scrape)
+			if summary.completed < summary.total {
+				fmt.Fprintf(os.Stderr, "Fetched metrics from %d of %d pods before timing out\n", summary.completed, summary.total)
+			}
+
+			if options.summary {
+				fmt.Print(renderMetricsSummary(parseMetrics(results)))
+				return nil
+			}
 
 			var buf bytes.Buffer
 			for i, result := range results {
@@ -107,6 +158,15 @@ func newCmdMetrics() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of resource")
+	cmd.PersistentFlags().DurationVarP(&options.wait, "wait", "w", options.wait, "Time allowed to fetch the metrics of all the pods")
+	cmd.PersistentFlags().IntVar(&options.concurrency, "concurrency", options.concurrency, "Maximum number of pods to fetch metrics from at the same time")
+	cmd.PersistentFlags().IntVar(&options.retries, "retries", options.retries, "Number of times a failed metrics request is retried")
+	cmd.PersistentFlags().DurationVar(&options.timeout, "request-timeout", options.timeout, "Time allowed for a single metrics request (0 means no timeout)")
+	cmd.PersistentFlags().StringVar(&options.path, "path", options.path, "URL path the metrics are exposed at")
+	cmd.PersistentFlags().StringVar(&options.scheme, "scheme", options.scheme, "URL scheme the metrics are served over; one of: \"http\" or \"https\"")
+	cmd.PersistentFlags().BoolVar(&options.insecure, "insecure-skip-verify", options.insecure, "If present, don't verify the certificate the metrics are served with over https")
+	cmd.PersistentFlags().StringVar(&options.node, "node", options.node, "If present, only fetch metrics from the pods scheduled on this node")
+	cmd.PersistentFlags().BoolVar(&options.summary, "summary", options.summary, "If present, only show the total inbound and outbound requests of each pod")
 
 	pkgcmd.ConfigureNamespaceFlagCompletion(cmd, []string{"namespace"},
 		kubeconfigPath, impersonate, impersonateGroup, kubeContext)
@@ -114,6 +174,26 @@ func newCmdMetrics() *cobra.Command {
 	return cmd
 }
 
+// renderMetricsSummary renders a table of the inbound and outbound request
+// totals of each of the parsed results, reporting their errors in place
+func renderMetricsSummary(results []parsedMetricsResult) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(w, "POD\tINBOUND\tOUTBOUND\t")
+	for _, result := range results {
+		if result.err != nil {
+			fmt.Fprintf(w, "%s\t-\t-\t(%s)\n", result.pod, result.err)
+			continue
+		}
+		inbound, _ := counterTotal(result.families, "request_total", map[string]string{"direction": "inbound"})
+		outbound, _ := counterTotal(result.families, "request_total", map[string]string{"direction": "outbound"})
+		fmt.Fprintf(w, "%s\t%.0f\t%.0f\t\n", result.pod, inbound, outbound)
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
 // getPodsFor takes a resource string, queries the Kubernetes API, and returns a
 // list of pods belonging to that resource.
 // This could move into `pkg/k8s` if becomes more generally useful.
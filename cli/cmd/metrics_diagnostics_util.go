@@ -1,18 +1,87 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
 	"sort"
-	"sync/atomic"
+	"strings"
 	"time"
 
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 	corev1 "k8s.io/api/core/v1"
 )
 
+const (
+	// defaultMetricsWait is the default time allowed to fetch the metrics of
+	// all the pods
+	defaultMetricsWait = 30 * time.Second
+
+	// defaultMetricsConcurrency is the default number of pods whose metrics
+	// are fetched at the same time
+	defaultMetricsConcurrency = 10
+
+	// defaultMetricsRetries is the default number of times a failed metrics
+	// request is retried
+	defaultMetricsRetries = 2
+
+	// defaultMetricsRequestTimeout is the default time allowed for a single
+	// metrics request. It's a fraction of defaultMetricsWait, so that a
+	// request that hangs leaves time to retry it. Requests are also cut short
+	// once the overall wait is over.
+	defaultMetricsRequestTimeout = defaultMetricsWait / 3
+
+	// defaultMetricsPath is the default path metrics are scraped from
+	defaultMetricsPath = "/metrics"
+
+	// defaultMetricsScheme is the default URL scheme metrics are scraped over
+	defaultMetricsScheme = httpScheme
+
+	httpScheme  = "http"
+	httpsScheme = "https"
+
+	// metricsRetryBackoff is the delay before the first retry of a failed
+	// metrics request, doubled for each subsequent retry
+	metricsRetryBackoff = 500 * time.Millisecond
+)
+
+// scrapeOptions configures how metrics are fetched from each container
+type scrapeOptions struct {
+	// retries is the number of times a failed request is retried
+	retries int
+	// timeout is the time allowed for a single request; zero means no timeout
+	timeout time.Duration
+	// path is the URL path metrics are exposed at
+	path string
+	// scheme is the URL scheme metrics are served over, http or https
+	scheme string
+	// insecureSkipVerify disables the verification of the certificate
+	// metrics are served with over https. The port-forward ends on
+	// localhost, so this is mostly useful for self-signed certificates.
+	insecureSkipVerify bool
+}
+
+// validate returns an error if opts holds an unsupported scheme or a path
+// that isn't absolute
+func (opts scrapeOptions) validate() error {
+	switch opts.scheme {
+	case "", httpScheme, httpsScheme:
+	default:
+		return fmt.Errorf("--scheme currently only supports %s and %s", httpScheme, httpsScheme)
+	}
+
+	if opts.path != "" && !strings.HasPrefix(opts.path, "/") {
+		return fmt.Errorf("--path must start with \"/\", was %q", opts.path)
+	}
+	return nil
+}
+
 // shared between metrics and diagnostics command
 type metricsResult struct {
 	pod       string
@@ -20,6 +89,29 @@ type metricsResult struct {
 	metrics   []byte
 	err       error
 }
+
+// parsedMetricsResult holds the metric families parsed out of a
+// metricsResult, keyed by metric name
+type parsedMetricsResult struct {
+	pod       string
+	container string
+	families  map[string]*dto.MetricFamily
+	err       error
+}
+
+// metricsSummary counts the pods whose metrics were fetched before the
+// deadline, out of all the pods that were scraped
+type metricsSummary struct {
+	completed int
+	total     int
+}
+
+// podMetricsResults holds the results for all the containers of a pod
+type podMetricsResults struct {
+	pod     string
+	results []metricsResult
+}
+
 type byResult []metricsResult
 
 func (s byResult) Len() int {
@@ -28,27 +120,78 @@ func (s byResult) Len() int {
 func (s byResult) Swap(i, j int) {
 	s[i], s[j] = s[j], s[i]
 }
+
+// Less orders results by pod and container. Results for the same pod and
+// container are ordered successes first, then by error message and metrics,
+// so that the order is fully deterministic.
 func (s byResult) Less(i, j int) bool {
-	return s[i].pod < s[j].pod || ((s[i].pod == s[j].pod) && s[i].container < s[j].container)
+	a, b := s[i], s[j]
+	if a.pod != b.pod {
+		return a.pod < b.pod
+	}
+	if a.container != b.container {
+		return a.container < b.container
+	}
+	if (a.err == nil) != (b.err == nil) {
+		return a.err == nil
+	}
+	if a.err != nil && a.err.Error() != b.err.Error() {
+		return a.err.Error() < b.err.Error()
+	}
+	return bytes.Compare(a.metrics, b.metrics) < 0
 }
 
-// getResponse makes a http Get request to the passed url and returns the response/error
-func getResponse(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+// getResponse makes a http Get request to the passed url using client and returns the response/error
+func getResponse(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status: %s", resp.Status)
+	}
 	return ioutil.ReadAll(resp.Body)
 }
 
+// getResponseWithRetries calls getResponse, retrying up to opts.retries times
+// with an exponential backoff if the request fails. It gives up as soon as
+// ctx is done, returning the last error.
+func getResponseWithRetries(ctx context.Context, url string, opts scrapeOptions) ([]byte, error) {
+	client := &http.Client{Timeout: opts.timeout}
+	if opts.insecureSkipVerify {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		client.Transport = transport
+	}
+	backoff := metricsRetryBackoff
+	for attempt := 0; ; attempt++ {
+		bytes, err := getResponse(ctx, client, url)
+		if err == nil || attempt >= opts.retries {
+			return bytes, err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, err
+		}
+		backoff *= 2
+	}
+}
+
 // getContainerMetrics returns the metrics exposed by a container on the passed in portName
 func getContainerMetrics(
+	ctx context.Context,
 	k8sAPI *k8s.KubernetesAPI,
 	pod corev1.Pod,
 	container corev1.Container,
 	emitLogs bool,
 	portName string,
+	opts scrapeOptions,
 ) ([]byte, error) {
 	portForward, err := k8s.NewContainerMetricsForward(k8sAPI, pod, container, emitLogs, portName)
 	if err != nil {
@@ -61,8 +204,16 @@ func getContainerMetrics(
 		return nil, err
 	}
 
-	metricsURL := portForward.URLFor("/metrics")
-	return getResponse(metricsURL)
+	path := opts.path
+	if path == "" {
+		path = defaultMetricsPath
+	}
+	scheme := opts.scheme
+	if scheme == "" {
+		scheme = defaultMetricsScheme
+	}
+	metricsURL := fmt.Sprintf("%s://%s%s", scheme, portForward.AddressAndPort(), path)
+	return getResponseWithRetries(ctx, metricsURL, opts)
 }
 
 // getAllContainersWithPort returns all the containers within
@@ -86,58 +237,198 @@ func getAllContainersWithPort(
 	return containers, nil
 }
 
+// filterPodsByNode returns the pods scheduled on the node named node, or all
+// the pods if node is empty. It returns an error if no pods are left.
+func filterPodsByNode(pods []corev1.Pod, node string) ([]corev1.Pod, error) {
+	if node == "" {
+		return pods, nil
+	}
+
+	var filtered []corev1.Pod
+	for _, pod := range pods {
+		if pod.Spec.NodeName == node {
+			filtered = append(filtered, pod)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("no pods found on node %s", node)
+	}
+	return filtered, nil
+}
+
 // getMetrics returns the metrics exposed by all the containers of the passed in list of pods
-// which exposes their metrics at portName
+// which exposes their metrics at portName. At most concurrency pods are scraped at the same
+// time, and pods whose metrics couldn't be fetched within waitingTime are reported as errors.
+// The returned summary counts the pods that completed before the deadline.
 func getMetrics(
 	k8sAPI *k8s.KubernetesAPI,
 	pods []corev1.Pod,
 	portName string,
 	waitingTime time.Duration,
+	concurrency int,
 	emitLogs bool,
-) []metricsResult {
-	var results []metricsResult
+	opts scrapeOptions,
+) ([]metricsResult, metricsSummary) {
+	scrape := func(ctx context.Context, pod corev1.Pod, container corev1.Container) ([]byte, error) {
+		return getContainerMetrics(ctx, k8sAPI, pod, container, emitLogs, portName, opts)
+	}
+	return scrapeMetrics(pods, portName, waitingTime, concurrency, scrape)
+}
+
+// scrapeMetrics calls scrape for all the containers of the passed in list of pods which
+// expose their metrics at portName, running at most concurrency pods at the same time.
+// Results received before waitingTime elapses are always returned, and the pods that
+// didn't finish in time are reported as timeout errors. The context passed to scrape is
+// canceled when waitingTime elapses, so that pending scrapes give up.
+func scrapeMetrics(
+	pods []corev1.Pod,
+	portName string,
+	waitingTime time.Duration,
+	concurrency int,
+	scrape func(context.Context, corev1.Pod, corev1.Container) ([]byte, error),
+) ([]metricsResult, metricsSummary) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitingTime)
+	defer cancel()
+
+	// buffered so that scrapes finishing after the deadline don't block forever
+	resultChan := make(chan podMetricsResults, len(pods))
+	sem := make(chan struct{}, concurrency)
+	pending := make(map[string]struct{}, len(pods))
 
-	resultChan := make(chan metricsResult)
-	var activeRoutines int32
 	for _, pod := range pods {
-		atomic.AddInt32(&activeRoutines, 1)
+		pending[pod.GetName()] = struct{}{}
 		go func(p corev1.Pod) {
-			defer atomic.AddInt32(&activeRoutines, -1)
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
 			containers, err := getAllContainersWithPort(p, portName)
 			if err != nil {
-				resultChan <- metricsResult{
+				resultChan <- podMetricsResults{
 					pod: p.GetName(),
-					err: err,
+					results: []metricsResult{{
+						pod: p.GetName(),
+						err: err,
+					}},
 				}
 				return
 			}
 
+			podResults := []metricsResult{}
 			for _, c := range containers {
-				bytes, err := getContainerMetrics(k8sAPI, p, c, emitLogs, portName)
+				bytes, err := scrape(ctx, p, c)
 
-				resultChan <- metricsResult{
+				podResults = append(podResults, metricsResult{
 					pod:       p.GetName(),
 					container: c.Name,
 					metrics:   bytes,
 					err:       err,
-				}
+				})
 			}
+			resultChan <- podMetricsResults{p.GetName(), podResults}
 		}(pod)
 	}
 
-	for {
+	summary := metricsSummary{completed: len(pending), total: len(pending)}
+	var results []metricsResult
+	collect := func(podResults podMetricsResults) {
+		delete(pending, podResults.pod)
+		results = append(results, podResults.results...)
+	}
+
+	for len(pending) > 0 {
 		select {
-		case result := <-resultChan:
-			results = append(results, result)
-		case <-time.After(waitingTime):
-			break // timed out
-		}
-		if atomic.LoadInt32(&activeRoutines) == 0 {
-			break
+		case podResults := <-resultChan:
+			collect(podResults)
+		case <-ctx.Done():
+			// keep the results that were already sent when the deadline hit
+		drain:
+			for len(pending) > 0 {
+				select {
+				case podResults := <-resultChan:
+					collect(podResults)
+				default:
+					break drain
+				}
+			}
+			for pod := range pending {
+				results = append(results, metricsResult{
+					pod: pod,
+					err: fmt.Errorf("timed out fetching metrics after %s", waitingTime),
+				})
+			}
+			summary.completed -= len(pending)
+			pending = nil
 		}
 	}
 
 	sort.Sort(byResult(results))
 
-	return results
+	return results, summary
+}
+
+// parseMetrics parses the Prometheus text exposition format scraped into each
+// of the passed in results. Scrape and parse errors are reported in the
+// corresponding parsedMetricsResult, without affecting the other results.
+func parseMetrics(results []metricsResult) []parsedMetricsResult {
+	parsed := make([]parsedMetricsResult, len(results))
+	for i, result := range results {
+		parsed[i] = parsedMetricsResult{
+			pod:       result.pod,
+			container: result.container,
+			err:       result.err,
+		}
+		if result.err != nil {
+			continue
+		}
+
+		var parser expfmt.TextParser
+		families, err := parser.TextToMetricFamilies(bytes.NewReader(result.metrics))
+		if err != nil {
+			parsed[i].err = fmt.Errorf("failed to parse metrics: %s", err)
+			continue
+		}
+		parsed[i].families = families
+	}
+	return parsed
+}
+
+// counterTotal returns the sum of the values of the counters named name whose
+// labels include all of the passed in labels, and whether any matched
+func counterTotal(families map[string]*dto.MetricFamily, name string, labels map[string]string) (float64, bool) {
+	family, ok := families[name]
+	if !ok || family.GetType() != dto.MetricType_COUNTER {
+		return 0, false
+	}
+
+	total := 0.0
+	found := false
+	for _, metric := range family.GetMetric() {
+		if !hasLabels(metric, labels) {
+			continue
+		}
+		total += metric.GetCounter().GetValue()
+		found = true
+	}
+	return total, found
+}
+
+func hasLabels(metric *dto.Metric, labels map[string]string) bool {
+	matched := 0
+	for _, pair := range metric.GetLabel() {
+		if value, ok := labels[pair.GetName()]; ok {
+			if value != pair.GetValue() {
+				return false
+			}
+			matched++
+		}
+	}
+	return matched == len(labels)
 }
@@ -4,9 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -61,3 +68,405 @@ spec:
 		})
 	}
 }
+
+func runningPod(name string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "linkerd-proxy",
+				Ports: []corev1.ContainerPort{{Name: k8s.ProxyAdminPortName}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestScrapeMetricsConcurrency(t *testing.T) {
+	pods := []corev1.Pod{}
+	for i := 0; i < 20; i++ {
+		pods = append(pods, runningPod(fmt.Sprintf("pod-%02d", i)))
+	}
+
+	var active, maxActive int32
+	scrape := func(context.Context, corev1.Pod, corev1.Container) ([]byte, error) {
+		current := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			max := atomic.LoadInt32(&maxActive)
+			if current <= max || atomic.CompareAndSwapInt32(&maxActive, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return []byte("metrics"), nil
+	}
+
+	results, summary := scrapeMetrics(pods, k8s.ProxyAdminPortName, 10*time.Second, 3, scrape)
+
+	if len(results) != len(pods) {
+		t.Fatalf("Expected %d results, got %d", len(pods), len(results))
+	}
+	for i, result := range results {
+		if result.err != nil {
+			t.Fatalf("Unexpected error for pod %s: %s", result.pod, result.err)
+		}
+		if result.pod != pods[i].GetName() {
+			t.Fatalf("Expected results sorted by pod, got %s at position %d", result.pod, i)
+		}
+	}
+	if maxActive > 3 {
+		t.Fatalf("Expected at most 3 concurrent scrapes, got %d", maxActive)
+	}
+	if summary.completed != len(pods) || summary.total != len(pods) {
+		t.Fatalf("Expected %d of %d pods completed, got %d of %d", len(pods), len(pods), summary.completed, summary.total)
+	}
+}
+
+func TestScrapeMetricsDeadline(t *testing.T) {
+	pods := []corev1.Pod{}
+	for i := 0; i < 10; i++ {
+		pods = append(pods, runningPod(fmt.Sprintf("pod-%02d", i)))
+	}
+
+	// a steady trickle of results: each scrape finishes 50ms after the
+	// previous one, so that results keep arriving well past the deadline
+	scrape := func(context.Context, corev1.Pod, corev1.Container) ([]byte, error) {
+		time.Sleep(50 * time.Millisecond)
+		return []byte("metrics"), nil
+	}
+
+	waitingTime := 120 * time.Millisecond
+	start := time.Now()
+	results, summary := scrapeMetrics(pods, k8s.ProxyAdminPortName, waitingTime, 1, scrape)
+	elapsed := time.Since(start)
+
+	if elapsed > 5*waitingTime {
+		t.Fatalf("Expected scrapeMetrics to return shortly after %s, took %s", waitingTime, elapsed)
+	}
+
+	if len(results) != len(pods) {
+		t.Fatalf("Expected %d results, got %d", len(pods), len(results))
+	}
+
+	timedOut := 0
+	for _, result := range results {
+		if result.err != nil {
+			timedOut++
+		}
+	}
+	if timedOut == 0 || timedOut == len(pods) {
+		t.Fatalf("Expected some but not all pods to time out, got %d timed out", timedOut)
+	}
+	if summary.total != len(pods) || summary.completed != len(pods)-timedOut {
+		t.Fatalf("Expected %d of %d pods completed, got %d of %d", len(pods)-timedOut, len(pods), summary.completed, summary.total)
+	}
+}
+
+func TestGetResponseWithRetries(t *testing.T) {
+	tests := []struct {
+		failures int32
+		retries  int
+		err      bool
+	}{
+		{0, 0, false},
+		{1, 1, false},
+		{2, 1, true},
+	}
+
+	for i, test := range tests {
+		test := test // pin
+		t.Run(fmt.Sprintf("%d: getResponseWithRetries returns expected result", i), func(t *testing.T) {
+			var requests int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) <= test.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Write([]byte("metrics"))
+			}))
+			defer ts.Close()
+
+			bytes, err := getResponseWithRetries(context.Background(), ts.URL, scrapeOptions{retries: test.retries})
+			if test.err {
+				if err == nil {
+					t.Fatal("Expected an error, got nothing")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error %s", err)
+			}
+			if string(bytes) != "metrics" {
+				t.Fatalf("Expected response \"metrics\", got %q", bytes)
+			}
+		})
+	}
+}
+
+func TestGetResponseOverHTTPS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("metrics"))
+	}))
+	defer ts.Close()
+
+	t.Run("Rejects self-signed certificates by default", func(t *testing.T) {
+		_, err := getResponseWithRetries(context.Background(), ts.URL, scrapeOptions{scheme: httpsScheme})
+		if err == nil {
+			t.Fatal("Expected a certificate error, got nothing")
+		}
+	})
+
+	t.Run("Accepts self-signed certificates when skipping verification", func(t *testing.T) {
+		bytes, err := getResponseWithRetries(context.Background(), ts.URL, scrapeOptions{scheme: httpsScheme, insecureSkipVerify: true})
+		if err != nil {
+			t.Fatalf("Unexpected error %s", err)
+		}
+		if string(bytes) != "metrics" {
+			t.Fatalf("Expected response \"metrics\", got %q", bytes)
+		}
+	})
+}
+
+func TestScrapeOptionsValidate(t *testing.T) {
+	for _, scheme := range []string{"", httpScheme, httpsScheme} {
+		if err := (scrapeOptions{scheme: scheme}).validate(); err != nil {
+			t.Fatalf("Unexpected error for scheme %q: %s", scheme, err)
+		}
+	}
+
+	for _, path := range []string{"", defaultMetricsPath, "/stats/prometheus"} {
+		if err := (scrapeOptions{path: path}).validate(); err != nil {
+			t.Fatalf("Unexpected error for path %q: %s", path, err)
+		}
+	}
+
+	for opts, expected := range map[scrapeOptions]string{
+		{scheme: "ftp"}:   "--scheme currently only supports http and https",
+		{path: "metrics"}: `--path must start with "/", was "metrics"`,
+		{path: ":9990/x"}: `--path must start with "/", was ":9990/x"`,
+		{path: "@evil/x"}: `--path must start with "/", was "@evil/x"`,
+	} {
+		err := opts.validate()
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error [%s], got [%v]", expected, err)
+		}
+	}
+}
+
+func TestGetResponseTimeout(t *testing.T) {
+	unblock := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer ts.Close()
+	defer close(unblock)
+
+	_, err := getResponseWithRetries(context.Background(), ts.URL, scrapeOptions{timeout: 50 * time.Millisecond})
+	if err == nil {
+		t.Fatal("Expected a timeout error, got nothing")
+	}
+}
+
+func TestGetResponseWithRetriesCanceled(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	// the deadline hits during the backoff before the first retry
+	ctx, cancel := context.WithTimeout(context.Background(), metricsRetryBackoff/2)
+	defer cancel()
+
+	start := time.Now()
+	_, err := getResponseWithRetries(ctx, ts.URL, scrapeOptions{retries: 5})
+	if err == nil {
+		t.Fatal("Expected an error, got nothing")
+	}
+	if elapsed := time.Since(start); elapsed >= metricsRetryBackoff {
+		t.Fatalf("Expected to give up when the context is done, took %s", elapsed)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("Expected a single request, got %d", n)
+	}
+}
+
+func TestScrapeMetricsCancelsScrapes(t *testing.T) {
+	canceled := make(chan struct{})
+	scrape := func(ctx context.Context, _ corev1.Pod, _ corev1.Container) ([]byte, error) {
+		<-ctx.Done()
+		close(canceled)
+		return nil, ctx.Err()
+	}
+
+	scrapeMetrics([]corev1.Pod{runningPod("pod-00")}, k8s.ProxyAdminPortName, 50*time.Millisecond, 1, scrape)
+
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the pending scrape to be canceled")
+	}
+}
+
+func TestParseMetrics(t *testing.T) {
+	metrics := `# HELP request_total Total count of HTTP requests.
+# TYPE request_total counter
+request_total{direction="inbound",authority="web:8080"} 10
+request_total{direction="inbound",authority="voting:8080"} 5
+request_total{direction="outbound",authority="web:8080"} 3
+# HELP process_start_time_seconds Time that the process started.
+# TYPE process_start_time_seconds gauge
+process_start_time_seconds 1600000000
+`
+	scrapeErr := errors.New("connection refused")
+
+	results := parseMetrics([]metricsResult{
+		{pod: "pod-ok", container: "linkerd-proxy", metrics: []byte(metrics)},
+		{pod: "pod-garbled", container: "linkerd-proxy", metrics: []byte("request_total{direction=\n")},
+		{pod: "pod-failed", container: "linkerd-proxy", err: scrapeErr},
+	})
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	ok := results[0]
+	if ok.err != nil {
+		t.Fatalf("Unexpected error: %s", ok.err)
+	}
+	if ok.pod != "pod-ok" || ok.container != "linkerd-proxy" {
+		t.Fatalf("Expected result for pod-ok/linkerd-proxy, got %s/%s", ok.pod, ok.container)
+	}
+	if len(ok.families) != 2 {
+		t.Fatalf("Expected 2 metric families, got %d", len(ok.families))
+	}
+
+	if results[1].err == nil {
+		t.Fatalf("Expected a parse error for pod-garbled, got nothing")
+	}
+	if results[2].err != scrapeErr {
+		t.Fatalf("Expected scrape error [%s] for pod-failed, got [%v]", scrapeErr, results[2].err)
+	}
+
+	counterTests := []struct {
+		name          string
+		labels        map[string]string
+		expectedTotal float64
+		expectedFound bool
+	}{
+		{"request_total", nil, 18, true},
+		{"request_total", map[string]string{"direction": "inbound"}, 15, true},
+		{"request_total", map[string]string{"direction": "inbound", "authority": "web:8080"}, 10, true},
+		{"request_total", map[string]string{"direction": "inbound", "tls": "true"}, 0, false},
+		{"process_start_time_seconds", nil, 0, false},
+		{"response_total", nil, 0, false},
+	}
+
+	for i, tc := range counterTests {
+		tc := tc // pin
+		t.Run(fmt.Sprintf("%d: %s", i, tc.name), func(t *testing.T) {
+			total, found := counterTotal(ok.families, tc.name, tc.labels)
+			if total != tc.expectedTotal || found != tc.expectedFound {
+				t.Fatalf("Expected (%v, %t), got (%v, %t)", tc.expectedTotal, tc.expectedFound, total, found)
+			}
+		})
+	}
+}
+
+func TestRenderMetricsSummary(t *testing.T) {
+	metrics := `# TYPE request_total counter
+request_total{direction="inbound",authority="web:8080"} 10
+request_total{direction="inbound",authority="voting:8080"} 5
+request_total{direction="outbound",authority="web:8080"} 3
+`
+
+	results := parseMetrics([]metricsResult{
+		{pod: "web-1", container: "linkerd-proxy", metrics: []byte(metrics)},
+		{pod: "web-2", container: "linkerd-proxy", metrics: []byte("# TYPE request_total counter\n")},
+		{pod: "web-3", container: "linkerd-proxy", err: errors.New("connection refused")},
+	})
+
+	expected := `POD     INBOUND   OUTBOUND   
+web-1   15        3          
+web-2   0         0          
+web-3   -         -          (connection refused)
+`
+	if output := renderMetricsSummary(results); output != expected {
+		t.Fatalf("Expected:\n%s\nGot:\n%s", expected, output)
+	}
+}
+
+func TestFilterPodsByNode(t *testing.T) {
+	pods := []corev1.Pod{}
+	for i, node := range []string{"node-1", "node-2", "node-1"} {
+		pod := runningPod(fmt.Sprintf("pod-%d", i))
+		pod.Spec.NodeName = node
+		pods = append(pods, pod)
+	}
+
+	testCases := []struct {
+		node          string
+		expectedPods  []string
+		expectedError string
+	}{
+		{"", []string{"pod-0", "pod-1", "pod-2"}, ""},
+		{"node-1", []string{"pod-0", "pod-2"}, ""},
+		{"node-3", nil, "no pods found on node node-3"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.node, func(t *testing.T) {
+			filtered, err := filterPodsByNode(pods, tc.node)
+			if tc.expectedError != "" {
+				if err == nil || err.Error() != tc.expectedError {
+					t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			var names []string
+			for _, pod := range filtered {
+				names = append(names, pod.GetName())
+			}
+			if !reflect.DeepEqual(names, tc.expectedPods) {
+				t.Fatalf("Expected pods %v, got %v", tc.expectedPods, names)
+			}
+		})
+	}
+}
+
+func TestByResultSortIsDeterministic(t *testing.T) {
+	expected := []metricsResult{
+		{pod: "pod-a", container: "linkerd-proxy", metrics: []byte("a")},
+		{pod: "pod-a", container: "linkerd-proxy", metrics: []byte("b")},
+		{pod: "pod-a", container: "linkerd-proxy", err: errors.New("connection refused")},
+		{pod: "pod-a", container: "linkerd-proxy", err: errors.New("timed out")},
+		{pod: "pod-a", container: "web", metrics: []byte("a")},
+		{pod: "pod-b", container: "linkerd-proxy", metrics: []byte("a")},
+	}
+
+	// sort every rotation of the results, so the duplicate keys start out in
+	// different orders
+	for i := range expected {
+		i := i // pin
+		t.Run(fmt.Sprintf("rotation %d", i), func(t *testing.T) {
+			results := append(append([]metricsResult{}, expected[i:]...), expected[:i]...)
+			sort.Sort(byResult(results))
+
+			for j := range results {
+				if results[j].pod != expected[j].pod ||
+					results[j].container != expected[j].container ||
+					string(results[j].metrics) != string(expected[j].metrics) ||
+					fmt.Sprint(results[j].err) != fmt.Sprint(expected[j].err) {
+					t.Fatalf("Unexpected result at index %d: expected %+v, got %+v", j, expected[j], results[j])
+				}
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"os"
 	"strings"
 	"time"
 
@@ -164,6 +165,13 @@ func makeInstallUpgradeFlags(defaults *l5dcharts.Values) ([]flag.Flag, *pflag.Fl
 				return nil
 			}),
 
+		flag.NewStringToStringFlag(installUpgradeFlags, "node-selector", nil,
+			"Additional node labels the control plane pods are scheduled by, as key=value pairs; merged with the default node selector",
+			func(values *l5dcharts.Values, value map[string]string) error {
+				values.NodeSelector = mergeNodeSelector(values.NodeSelector, value)
+				return nil
+			}),
+
 		flag.NewStringFlag(installUpgradeFlags, "control-plane-version", defaults.ControllerImageVersion,
 			"Tag to be used for the control plane component images",
 			func(values *l5dcharts.Values, value string) error {
@@ -186,6 +194,19 @@ func makeInstallUpgradeFlags(defaults *l5dcharts.Values) ([]flag.Flag, *pflag.Fl
 	return flags, installUpgradeFlags, nil
 }
 
+// mergeNodeSelector returns the labels of selector with the ones of overrides
+// added, overrides taking precedence for the keys present in both
+func mergeNodeSelector(selector, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(selector)+len(overrides))
+	for k, v := range selector {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
 func loadCrtPEM(path string) (string, error) {
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -521,6 +542,13 @@ func validateValues(ctx context.Context, k *k8s.KubernetesAPI, values *l5dcharts
 		return err
 	}
 
+	if err := values.Validate(); err != nil {
+		return err
+	}
+	for _, warning := range values.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+
 	if values.Identity.Issuer.Scheme == string(corev1.SecretTypeTLS) {
 		if values.Identity.Issuer.TLS.CrtPEM != "" {
 			return errors.New("--identity-issuer-certificate-file must not be specified if --identity-external-issuer=true")
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	pkgcmd "github.com/linkerd/linkerd2/pkg/cmd"
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type proxyLogsOptions struct {
+	namespace string
+	since     time.Duration
+}
+
+func newProxyLogsOptions() *proxyLogsOptions {
+	return &proxyLogsOptions{
+		since: 0,
+	}
+}
+
+// proxyLogsTarget is a proxy container whose logs are streamed
+type proxyLogsTarget struct {
+	namespace string
+	pod       string
+	container string
+}
+
+func newCmdProxyLogs() *cobra.Command {
+	options := newProxyLogsOptions()
+
+	cmd := &cobra.Command{
+		Use:   "proxy-logs [flags] (RESOURCE)",
+		Short: "Stream the logs of the Linkerd proxies of a resource",
+		Long: `Stream the logs of the Linkerd proxies of a resource.
+
+  This command follows the logs of the linkerd-proxy container of every
+  running pod of the given resource at the same time, prefixing each line with
+  the name of its pod, until interrupted.
+
+  The RESOURCE argument specifies the target resource to stream logs from:
+  (TYPE/NAME)
+
+  Examples:
+  * cronjob/my-cronjob
+  * deploy/my-deploy
+  * ds/my-daemonset
+  * job/my-job
+  * po/mypod1
+  * rc/my-replication-controller
+  * sts/my-statefulset`,
+		Example: `  # Stream the proxy logs of the web deployment in the emojivoto namespace.
+  linkerd diagnostics proxy-logs -n emojivoto deploy/web
+
+  # Stream the proxy logs of the web deployment, starting 5 minutes ago.
+  linkerd diagnostics proxy-logs -n emojivoto deploy/web --since 5m`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if options.since < 0 {
+				return errors.New("--since must not be negative")
+			}
+			if options.namespace == "" {
+				options.namespace = pkgcmd.GetDefaultNamespace(kubeconfigPath, kubeContext)
+			}
+			k8sAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup, 0)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			pods, err := getPodsFor(ctx, k8sAPI, options.namespace, args[0])
+			if err != nil {
+				return err
+			}
+
+			targets := getProxyLogsTargets(pods)
+			if len(targets) == 0 {
+				return fmt.Errorf("no running meshed pods found for %s", args[0])
+			}
+
+			return streamProxyLogs(ctx, k8sAPI, targets, options.since, stdout)
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of resource")
+	cmd.PersistentFlags().DurationVar(&options.since, "since", options.since, "Only return logs newer than this duration (for example: \"30s\", \"5m\"); by default all the logs are returned")
+
+	pkgcmd.ConfigureNamespaceFlagCompletion(cmd, []string{"namespace"},
+		kubeconfigPath, impersonate, impersonateGroup, kubeContext)
+
+	return cmd
+}
+
+// getProxyLogsTargets returns the proxy containers of the running pods in
+// pods, found through their admin port
+func getProxyLogsTargets(pods []corev1.Pod) []proxyLogsTarget {
+	var targets []proxyLogsTarget
+	for _, pod := range pods {
+		containers, err := getAllContainersWithPort(pod, k8s.ProxyAdminPortName)
+		if err != nil {
+			continue
+		}
+		for _, c := range containers {
+			targets = append(targets, proxyLogsTarget{
+				namespace: pod.GetNamespace(),
+				pod:       pod.GetName(),
+				container: c.Name,
+			})
+		}
+	}
+	return targets
+}
+
+// streamProxyLogs follows the logs of all the targets concurrently, writing
+// each line to w prefixed by its pod name. It returns once all the streams
+// have ended, or ctx is canceled.
+func streamProxyLogs(ctx context.Context, client kubernetes.Interface, targets []proxyLogsTarget, since time.Duration, w io.Writer) error {
+	logOptions := corev1.PodLogOptions{Follow: true}
+	if since > 0 {
+		sinceSeconds := int64(since.Seconds())
+		logOptions.SinceSeconds = &sinceSeconds
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(chan error, len(targets))
+
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target proxyLogsTarget) {
+			defer wg.Done()
+
+			opts := logOptions
+			opts.Container = target.container
+			stream, err := client.CoreV1().Pods(target.namespace).GetLogs(target.pod, &opts).Stream(ctx)
+			if err != nil {
+				errs <- fmt.Errorf("failed to stream logs of %s: %s", target.pod, err)
+				return
+			}
+			defer stream.Close()
+
+			scanner := bufio.NewScanner(stream)
+			for scanner.Scan() {
+				mu.Lock()
+				fmt.Fprintf(w, "[%s] %s\n", target.pod, scanner.Text())
+				mu.Unlock()
+			}
+			if err := scanner.Err(); err != nil && ctx.Err() == nil {
+				errs <- fmt.Errorf("failed to read logs of %s: %s", target.pod, err)
+			}
+		}(target)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	// interrupting the command isn't an error
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err, ok := <-errs; ok {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/k8s"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetProxyLogsTargets(t *testing.T) {
+	meshed := runningPod("meshed")
+	meshed.Namespace = "emojivoto"
+	meshed.Spec.Containers = append(meshed.Spec.Containers, corev1.Container{Name: "web"})
+
+	stopped := runningPod("stopped")
+	stopped.Status.Phase = corev1.PodSucceeded
+
+	unmeshed := runningPod("unmeshed")
+	unmeshed.Spec.Containers = []corev1.Container{{Name: "web"}}
+
+	targets := getProxyLogsTargets([]corev1.Pod{meshed, stopped, unmeshed})
+
+	expected := []proxyLogsTarget{{namespace: "emojivoto", pod: "meshed", container: k8s.ProxyContainerName}}
+	if !reflect.DeepEqual(targets, expected) {
+		t.Fatalf("Expected targets %+v, got %+v", expected, targets)
+	}
+}
+
+func TestStreamProxyLogs(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	targets := []proxyLogsTarget{
+		{namespace: "emojivoto", pod: "web-1", container: k8s.ProxyContainerName},
+		{namespace: "emojivoto", pod: "web-2", container: k8s.ProxyContainerName},
+	}
+
+	var buf bytes.Buffer
+	if err := streamProxyLogs(context.Background(), k8sAPI, targets, 0, &buf); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// the fake clientset returns "fake logs" for every container
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d: %q", len(lines), buf.String())
+	}
+	for _, expected := range []string{"[web-1] fake logs", "[web-2] fake logs"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Fatalf("Expected output to contain %q, got %q", expected, buf.String())
+		}
+	}
+}
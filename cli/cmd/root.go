@@ -26,6 +26,7 @@ const (
 	jsonOutput  = healthcheck.JSONOutput
 	tableOutput = healthcheck.TableOutput
 	shortOutput = healthcheck.ShortOutput
+	yamlOutput  = "yaml"
 )
 
 var (
@@ -88,7 +89,7 @@ var RootCmd = &cobra.Command{
 			return fmt.Errorf("%s is not a valid namespace", controlPlaneNamespace)
 		}
 
-		return nil
+		return pkgcmd.ValidateImpersonation(impersonate, impersonateGroup)
 	},
 }
 
@@ -104,6 +105,7 @@ func init() {
 	RootCmd.AddCommand(newCmdAlpha())
 	RootCmd.AddCommand(newCmdCheck())
 	RootCmd.AddCommand(newCmdCompletion())
+	RootCmd.AddCommand(newCmdConfig())
 	RootCmd.AddCommand(newCmdDiagnostics())
 	RootCmd.AddCommand(newCmdDoc())
 	RootCmd.AddCommand(newCmdIdentity())
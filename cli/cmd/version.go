@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"runtime"
 	"time"
 
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
@@ -72,6 +73,10 @@ func configureAndRunVersion(
 		fmt.Fprintln(stdout, clientVersion)
 	} else {
 		fmt.Fprintf(stdout, "Client version: %s\n", clientVersion)
+		if version.GitSHA != "" {
+			fmt.Fprintf(stdout, "Client git SHA: %s\n", version.GitSHA)
+		}
+		fmt.Fprintf(stdout, "Client Go version: %s\n", runtime.Version())
 	}
 
 	if !options.onlyClientVersion {
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/linkerd/linkerd2/pkg/version"
+)
+
+func TestConfigureAndRunVersion(t *testing.T) {
+	testCases := []struct {
+		options  *versionOptions
+		expected string
+	}{
+		{
+			&versionOptions{onlyClientVersion: true},
+			fmt.Sprintf("Client version: %s\nClient Go version: %s\n", version.Version, runtime.Version()),
+		},
+		{
+			&versionOptions{onlyClientVersion: true, shortVersion: true},
+			fmt.Sprintf("%s\n", version.Version),
+		},
+	}
+
+	for i, tc := range testCases {
+		tc := tc // pin
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			// a nil KubernetesAPI ensures no server round trip is attempted
+			stdout := bytes.Buffer{}
+			configureAndRunVersion(nil, tc.options, &stdout)
+
+			if stdout.String() != tc.expected {
+				t.Fatalf("Expected output: \"%s\", got: \"%s\"", tc.expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestConfigureAndRunVersionGitSHA(t *testing.T) {
+	defer func(sha string) { version.GitSHA = sha }(version.GitSHA)
+	version.GitSHA = "0123456789abcdef0123456789abcdef01234567"
+
+	stdout := bytes.Buffer{}
+	configureAndRunVersion(nil, &versionOptions{onlyClientVersion: true}, &stdout)
+	expected := fmt.Sprintf("Client version: %s\nClient git SHA: %s\nClient Go version: %s\n", version.Version, version.GitSHA, runtime.Version())
+	if stdout.String() != expected {
+		t.Fatalf("Expected output: \"%s\", got: \"%s\"", expected, stdout.String())
+	}
+
+	stdout = bytes.Buffer{}
+	configureAndRunVersion(nil, &versionOptions{onlyClientVersion: true, shortVersion: true}, &stdout)
+	expected = fmt.Sprintf("%s\n", version.Version)
+	if stdout.String() != expected {
+		t.Fatalf("Expected output: \"%s\", got: \"%s\"", expected, stdout.String())
+	}
+}
@@ -50,6 +50,15 @@ type (
 		apply   func(values *charts.Values, value []string) error
 	}
 
+	// StringToStringFlag is a Flag with a map[string]string typed value, set
+	// as comma-separated key=value pairs.
+	StringToStringFlag struct {
+		name    string
+		Value   map[string]string
+		flagSet *pflag.FlagSet
+		apply   func(values *charts.Values, value map[string]string) error
+	}
+
 	// BoolFlag is a Flag with a bool typed value.
 	BoolFlag struct {
 		name    string
@@ -115,6 +124,18 @@ func NewStringSliceFlag(flagSet *pflag.FlagSet, name string, defaultValue []stri
 	return &flag
 }
 
+// NewStringToStringFlag creates a new map[string]string typed Flag that executes the given function
+// when applied.  The flag is attached to the given FlagSet.
+func NewStringToStringFlag(flagSet *pflag.FlagSet, name string, defaultValue map[string]string, description string, apply func(values *charts.Values, value map[string]string) error) *StringToStringFlag {
+	flag := StringToStringFlag{
+		name:    name,
+		flagSet: flagSet,
+		apply:   apply,
+	}
+	flagSet.StringToStringVar(&flag.Value, name, defaultValue, description)
+	return &flag
+}
+
 // NewStringFlagP creates a new string typed Flag that executes the given function
 // when applied.  The flag is attached to the given FlagSet.
 func NewStringFlagP(flagSet *pflag.FlagSet, name string, short string, defaultValue string, description string, apply func(values *charts.Values, value string) error) *StringFlag {
@@ -211,6 +232,21 @@ func (flag *StringSliceFlag) Name() string {
 	return flag.name
 }
 
+// Apply executes the stored apply function on the given Values.
+func (flag *StringToStringFlag) Apply(values *charts.Values) error {
+	return flag.apply(values, flag.Value)
+}
+
+// IsSet returns true if and only if the Flag has been explicitly set with a value.
+func (flag *StringToStringFlag) IsSet() bool {
+	return flag.flagSet.Changed(flag.name)
+}
+
+// Name returns the name of the flag.
+func (flag *StringToStringFlag) Name() string {
+	return flag.name
+}
+
 // Apply executes the stored apply function on the given Values.
 func (flag *BoolFlag) Apply(values *charts.Values) error {
 	return flag.apply(values, flag.Value)
@@ -2,6 +2,10 @@ package destination
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 
 	pb "github.com/linkerd/linkerd2-proxy-api/go/destination"
 	"github.com/linkerd/linkerd2/pkg/k8s"
@@ -11,13 +15,36 @@ import (
 
 const (
 	destinationPort       = 8086
+	destinationAdminPort  = 9996
 	destinationDeployment = "linkerd-destination"
+
+	// DefaultMaxMessageSize is the default size limit, in bytes, of the
+	// messages received by the Destination API clients. It's the same 4MB
+	// gRPC applies by default to received messages. The server doesn't limit
+	// the size of the responses it sends, so large responses only need a
+	// larger limit on the client side.
+	DefaultMaxMessageSize = 4 * 1024 * 1024
 )
 
+// ErrSelfCheckNotFound is returned when the destination controller doesn't
+// serve the self-check, as is the case for versions predating it.
+var ErrSelfCheckNotFound = errors.New("the destination controller doesn't serve the self-check")
+
 // NewClient creates a client for the control plane Destination API that
 // implements the Destination service.
 func NewClient(addr string) (pb.DestinationClient, *grpc.ClientConn, error) {
-	conn, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithStatsHandler(&ocgrpc.ClientHandler{}))
+	return NewClientWithMaxMessageSize(addr, DefaultMaxMessageSize)
+}
+
+// NewClientWithMaxMessageSize creates a client for the control plane
+// Destination API that receives messages of up to maxMessageSize bytes.
+func NewClientWithMaxMessageSize(addr string, maxMessageSize int) (pb.DestinationClient, *grpc.ClientConn, error) {
+	conn, err := grpc.Dial(
+		addr,
+		grpc.WithInsecure(),
+		grpc.WithStatsHandler(&ocgrpc.ClientHandler{}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMessageSize)),
+	)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -28,6 +55,13 @@ func NewClient(addr string) (pb.DestinationClient, *grpc.ClientConn, error) {
 // NewExternalClient creates a client for the control plane Destination API
 // to run from outside a Kubernetes cluster.
 func NewExternalClient(ctx context.Context, controlPlaneNamespace string, kubeAPI *k8s.KubernetesAPI) (pb.DestinationClient, *grpc.ClientConn, error) {
+	return NewExternalClientWithMaxMessageSize(ctx, controlPlaneNamespace, kubeAPI, DefaultMaxMessageSize)
+}
+
+// NewExternalClientWithMaxMessageSize creates a client for the control plane
+// Destination API to run from outside a Kubernetes cluster, that sends and
+// receives messages of up to maxMessageSize bytes.
+func NewExternalClientWithMaxMessageSize(ctx context.Context, controlPlaneNamespace string, kubeAPI *k8s.KubernetesAPI, maxMessageSize int) (pb.DestinationClient, *grpc.ClientConn, error) {
 	portforward, err := k8s.NewPortForward(
 		ctx,
 		kubeAPI,
@@ -47,5 +81,56 @@ func NewExternalClient(ctx context.Context, controlPlaneNamespace string, kubeAP
 		return nil, nil, err
 	}
 
-	return NewClient(destinationAddress)
+	return NewClientWithMaxMessageSize(destinationAddress, maxMessageSize)
+}
+
+// GetExternalSelfCheck fetches the SelfCheckResult of the destination
+// controller from outside a Kubernetes cluster.
+func GetExternalSelfCheck(ctx context.Context, controlPlaneNamespace string, kubeAPI *k8s.KubernetesAPI) (*SelfCheckResult, error) {
+	portforward, err := k8s.NewPortForward(
+		ctx,
+		kubeAPI,
+		controlPlaneNamespace,
+		destinationDeployment,
+		"localhost",
+		0,
+		destinationAdminPort,
+		false,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer portforward.Stop()
+
+	if err = portforward.Init(); err != nil {
+		return nil, err
+	}
+
+	return getSelfCheck(ctx, http.DefaultClient, portforward.URLFor(SelfCheckPath))
+}
+
+func getSelfCheck(ctx context.Context, client *http.Client, url string) (*SelfCheckResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode == http.StatusNotFound {
+		return nil, ErrSelfCheckNotFound
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected self-check response status: %s", rsp.Status)
+	}
+
+	var result SelfCheckResult
+	if err := json.NewDecoder(rsp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode self-check response: %s", err)
+	}
+	return &result, nil
 }
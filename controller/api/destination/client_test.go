@@ -0,0 +1,68 @@
+package destination
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	pb "github.com/linkerd/linkerd2-proxy-api/go/destination"
+	"github.com/linkerd/linkerd2/pkg/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// largeProfileServer answers GetProfile with a single profile whose name is
+// size bytes long
+type largeProfileServer struct {
+	pb.UnimplementedDestinationServer
+	size int
+}
+
+func (s *largeProfileServer) GetProfile(_ *pb.GetDestination, stream pb.Destination_GetProfileServer) error {
+	return stream.Send(&pb.DestinationProfile{
+		FullyQualifiedName: strings.Repeat("a", s.size),
+	})
+}
+
+func TestNewClientWithMaxMessageSize(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	s := prometheus.NewGrpcServer(grpc.MaxSendMsgSize(2 * DefaultMaxMessageSize))
+	pb.RegisterDestinationServer(s, &largeProfileServer{size: DefaultMaxMessageSize + 1024})
+	go s.Serve(lis)
+	defer s.Stop()
+
+	testCases := []struct {
+		maxMessageSize int
+		code           codes.Code
+	}{
+		{DefaultMaxMessageSize, codes.ResourceExhausted},
+		{2 * DefaultMaxMessageSize, codes.OK},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(fmt.Sprintf("max message size %d", tc.maxMessageSize), func(t *testing.T) {
+			client, conn, err := NewClientWithMaxMessageSize(lis.Addr().String(), tc.maxMessageSize)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			defer conn.Close()
+
+			stream, err := client.GetProfile(context.Background(), &pb.GetDestination{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			_, err = stream.Recv()
+			if code := status.Code(err); code != tc.code {
+				t.Fatalf("Expected code %s, got %s (%v)", tc.code, code, err)
+			}
+		})
+	}
+}
@@ -205,7 +205,9 @@ func (et *endpointTranslator) NoEndpoints(exists bool) {
 	et.log.Debugf("Sending destination no endpoints: %+v", u)
 	if err := et.stream.Send(u); err != nil {
 		et.log.Errorf("Failed to send address update: %s", err)
+		return
 	}
+	endpointUpdatesSent.WithLabelValues(noEndpointsUpdate).Inc()
 }
 
 func (et *endpointTranslator) sendClientAdd(set watcher.AddressSet) {
@@ -284,7 +286,9 @@ func (et *endpointTranslator) sendClientAdd(set watcher.AddressSet) {
 	et.log.Debugf("Sending destination add: %+v", add)
 	if err := et.stream.Send(add); err != nil {
 		et.log.Errorf("Failed to send address update: %s", err)
+		return
 	}
+	endpointUpdatesSent.WithLabelValues(addUpdate).Inc()
 }
 
 func (et *endpointTranslator) sendClientRemove(set watcher.AddressSet) {
@@ -307,7 +311,9 @@ func (et *endpointTranslator) sendClientRemove(set watcher.AddressSet) {
 	et.log.Debugf("Sending destination remove: %+v", remove)
 	if err := et.stream.Send(remove); err != nil {
 		et.log.Errorf("Failed to send address update: %s", err)
+		return
 	}
+	endpointUpdatesSent.WithLabelValues(removeUpdate).Inc()
 }
 
 func toAddr(address watcher.Address) (*net.TcpAddress, error) {
@@ -13,6 +13,7 @@ import (
 	pkgk8s "github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/addr"
 	"github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	logging "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -262,6 +263,8 @@ func TestEndpointTranslatorForRemoteGateways(t *testing.T) {
 func TestEndpointTranslatorForPods(t *testing.T) {
 	t.Run("Sends one update for add and another for remove", func(t *testing.T) {
 		mockGetServer, translator := makeEndpointTranslator(t)
+		addsBefore := testutil.ToFloat64(endpointUpdatesSent.WithLabelValues(addUpdate))
+		removesBefore := testutil.ToFloat64(endpointUpdatesSent.WithLabelValues(removeUpdate))
 
 		translator.Add(mkAddressSetForPods(normalPod, tlsOptionalPod))
 		translator.Remove(mkAddressSetForPods(tlsOptionalPod))
@@ -271,6 +274,13 @@ func TestEndpointTranslatorForPods(t *testing.T) {
 		if actualNumUpdates != expectedNumUpdates {
 			t.Fatalf("Expecting [%d] updates, got [%d]. Updates: %v", expectedNumUpdates, actualNumUpdates, mockGetServer.updatesReceived)
 		}
+
+		if adds := testutil.ToFloat64(endpointUpdatesSent.WithLabelValues(addUpdate)) - addsBefore; adds != 1 {
+			t.Fatalf("Expecting 1 add update to be counted, got %v", adds)
+		}
+		if removes := testutil.ToFloat64(endpointUpdatesSent.WithLabelValues(removeUpdate)) - removesBefore; removes != 1 {
+			t.Fatalf("Expecting 1 remove update to be counted, got %v", removes)
+		}
 	})
 
 	t.Run("Sends addresses as removed or added", func(t *testing.T) {
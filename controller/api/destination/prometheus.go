@@ -0,0 +1,53 @@
+package destination
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	getMethod        = "get"
+	getProfileMethod = "get_profile"
+
+	addUpdate         = "add"
+	removeUpdate      = "remove"
+	noEndpointsUpdate = "no_endpoints"
+)
+
+var (
+	activeStreams = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "destination_active_streams",
+			Help: "A gauge for the current number of Get and GetProfile streams served, by method.",
+		},
+		[]string{"method"},
+	)
+
+	resolutionErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "destination_resolution_errors",
+			Help: "A counter for the number of Get and GetProfile streams that ended with an error, by method.",
+		},
+		[]string{"method"},
+	)
+
+	endpointUpdatesSent = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "destination_endpoint_updates_sent",
+			Help: "A counter for the number of endpoint updates sent to the proxies, by update type.",
+		},
+		[]string{"update"},
+	)
+)
+
+// trackStream counts a stream served by method as active until the returned
+// function is called with the error the stream ended with
+func trackStream(method string) func(error) {
+	activeStreams.WithLabelValues(method).Inc()
+	return func(err error) {
+		activeStreams.WithLabelValues(method).Dec()
+		if err != nil {
+			resolutionErrors.WithLabelValues(method).Inc()
+		}
+	}
+}
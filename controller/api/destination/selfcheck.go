@@ -0,0 +1,44 @@
+package destination
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/linkerd/linkerd2/controller/api/destination/watcher"
+)
+
+// SelfCheckPath is the path the destination self-check is served at, on the
+// destination controller's admin server
+const SelfCheckPath = "/self-check"
+
+// SelfCheckResult reports the health of the destination controller
+type SelfCheckResult struct {
+	// InformersSynced is true once the caches of all the informers the
+	// destination server reads from are synced
+	InformersSynced bool `json:"informersSynced"`
+	// WatchedServices is the number of services the endpoints watcher tracks
+	WatchedServices int `json:"watchedServices"`
+}
+
+// SelfCheck is an http.Handler serving the destination server's
+// SelfCheckResult as JSON
+type SelfCheck struct {
+	hasSynced func() bool
+	endpoints *watcher.EndpointsWatcher
+}
+
+// Result returns the current SelfCheckResult
+func (c *SelfCheck) Result() SelfCheckResult {
+	return SelfCheckResult{
+		InformersSynced: c.hasSynced(),
+		WatchedServices: c.endpoints.ServiceCount(),
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (c *SelfCheck) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(c.Result()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,53 @@
+package destination
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSelfCheck(t *testing.T) {
+	s := makeServer(t)
+	selfCheck := &SelfCheck{s.k8sAPI.HasSynced, s.endpoints}
+
+	ts := httptest.NewServer(selfCheck)
+	defer ts.Close()
+
+	result, err := getSelfCheck(context.Background(), ts.Client(), ts.URL+SelfCheckPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !result.InformersSynced {
+		t.Fatal("Expected informers to be synced")
+	}
+	if result.WatchedServices != s.endpoints.ServiceCount() {
+		t.Fatalf("Expected %d watched services, got %d", s.endpoints.ServiceCount(), result.WatchedServices)
+	}
+	if result.WatchedServices == 0 {
+		t.Fatal("Expected the services of the fake API to be watched")
+	}
+}
+
+func TestGetSelfCheckError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	_, err := getSelfCheck(context.Background(), ts.Client(), ts.URL+SelfCheckPath)
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+}
+
+func TestGetSelfCheckNotFound(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	_, err := getSelfCheck(context.Background(), ts.Client(), ts.URL+SelfCheckPath)
+	if !errors.Is(err, ErrSelfCheckNotFound) {
+		t.Fatalf("Expected %q, got %v", ErrSelfCheckNotFound, err)
+	}
+}
@@ -56,6 +56,9 @@ type (
 //
 // Addresses for the given destination are fetched from the Kubernetes Endpoints
 // API.
+//
+// The returned SelfCheck reports the readiness of the server, and is meant to
+// be served at SelfCheckPath.
 func NewServer(
 	addr string,
 	controllerNS string,
@@ -66,7 +69,7 @@ func NewServer(
 	clusterDomain string,
 	defaultOpaquePorts map[uint32]struct{},
 	shutdown <-chan struct{},
-) (*grpc.Server, error) {
+) (*grpc.Server, *SelfCheck, error) {
 	log := logging.WithFields(logging.Fields{
 		"addr":      addr,
 		"component": "server",
@@ -75,7 +78,7 @@ func NewServer(
 	// Initialize indexers that are used across watchers
 	err := watcher.InitializeIndexers(k8sAPI)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	endpoints := watcher.NewEndpointsWatcher(k8sAPI, log, enableEndpointSlices)
@@ -102,10 +105,13 @@ func NewServer(
 	s := prometheus.NewGrpcServer()
 	// linkerd2-proxy-api/destination.Destination (proxy-facing)
 	pb.RegisterDestinationServer(s, &srv)
-	return s, nil
+	return s, &SelfCheck{k8sAPI.HasSynced, endpoints}, nil
 }
 
-func (s *server) Get(dest *pb.GetDestination, stream pb.Destination_GetServer) error {
+func (s *server) Get(dest *pb.GetDestination, stream pb.Destination_GetServer) (err error) {
+	done := trackStream(getMethod)
+	defer func() { done(err) }()
+
 	client, _ := peer.FromContext(stream.Context())
 	log := s.log
 	if client != nil {
@@ -119,18 +125,6 @@ func (s *server) Get(dest *pb.GetDestination, stream pb.Destination_GetServer) e
 		log.Debugf("Dest token: %v", token)
 	}
 
-	translator := newEndpointTranslator(
-		s.controllerNS,
-		s.identityTrustDomain,
-		s.enableH2Upgrade,
-		dest.GetPath(),
-		token.NodeName,
-		s.defaultOpaquePorts,
-		s.nodes,
-		stream,
-		log,
-	)
-
 	// The host must be fully-qualified or be an IP address.
 	host, port, err := getHostAndPort(dest.GetPath())
 	if err != nil {
@@ -149,6 +143,23 @@ func (s *server) Get(dest *pb.GetDestination, stream pb.Destination_GetServer) e
 		return status.Errorf(codes.InvalidArgument, "Invalid authority: %s", dest.GetPath())
 	}
 
+	enableH2Upgrade := s.enableH2Upgrade
+	if svc, err := s.k8sAPI.Svc().Lister().Services(service.Namespace).Get(service.Name); err == nil {
+		enableH2Upgrade = h2UpgradeEnabled(svc, s.enableH2Upgrade, log)
+	}
+
+	translator := newEndpointTranslator(
+		s.controllerNS,
+		s.identityTrustDomain,
+		enableH2Upgrade,
+		dest.GetPath(),
+		token.NodeName,
+		s.defaultOpaquePorts,
+		s.nodes,
+		stream,
+		log,
+	)
+
 	err = s.endpoints.Subscribe(service, port, instanceID, translator)
 	if err != nil {
 		if _, ok := err.(watcher.InvalidService); ok {
@@ -169,7 +180,10 @@ func (s *server) Get(dest *pb.GetDestination, stream pb.Destination_GetServer) e
 	return nil
 }
 
-func (s *server) GetProfile(dest *pb.GetDestination, stream pb.Destination_GetProfileServer) error {
+func (s *server) GetProfile(dest *pb.GetDestination, stream pb.Destination_GetProfileServer) (err error) {
+	done := trackStream(getProfileMethod)
+	defer func() { done(err) }()
+
 	log := s.log
 	client, _ := peer.FromContext(stream.Context())
 	if client != nil {
@@ -657,6 +671,22 @@ func hasSuffix(slice []string, suffix []string) bool {
 	return true
 }
 
+// h2UpgradeEnabled returns whether the endpoints of svc should be hinted as
+// supporting H2 upgrade, which is enabledByDefault unless the service
+// overrides it with the enable-h2-upgrade annotation
+func h2UpgradeEnabled(svc *corev1.Service, enabledByDefault bool, log *logging.Entry) bool {
+	annotation, ok := svc.Annotations[labels.EnableH2UpgradeAnnotation]
+	if !ok {
+		return enabledByDefault
+	}
+	enabled, err := strconv.ParseBool(annotation)
+	if err != nil {
+		log.Warnf("Invalid %s annotation on service %s/%s: %s", labels.EnableH2UpgradeAnnotation, svc.Namespace, svc.Name, err)
+		return enabledByDefault
+	}
+	return enabled
+}
+
 func getPodOpaquePortsAnnotations(pod *corev1.Pod) (map[uint32]struct{}, bool, error) {
 	annotation, ok := pod.Annotations[labels.ProxyOpaquePortsAnnotation]
 	if !ok {
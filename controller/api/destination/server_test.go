@@ -11,7 +11,11 @@ import (
 	"github.com/linkerd/linkerd2/controller/api/util"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/addr"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	logging "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const fullyQualifiedName = "name1.ns.svc.mycluster.local"
@@ -393,10 +397,19 @@ func TestGet(t *testing.T) {
 			MockServerStream: util.NewMockServerStream(),
 		}
 
+		errorsBefore := testutil.ToFloat64(resolutionErrors.WithLabelValues(getMethod))
+
 		err := server.Get(&pb.GetDestination{Scheme: "k8s", Path: "linkerd.io"}, stream)
 		if err == nil {
 			t.Fatalf("Expecting error, got nothing")
 		}
+
+		if errs := testutil.ToFloat64(resolutionErrors.WithLabelValues(getMethod)) - errorsBefore; errs != 1 {
+			t.Fatalf("Expecting 1 resolution error to be counted, got %v", errs)
+		}
+		if active := testutil.ToFloat64(activeStreams.WithLabelValues(getMethod)); active != 0 {
+			t.Fatalf("Expecting no active streams, got %v", active)
+		}
 	})
 
 	t.Run("Returns endpoints", func(t *testing.T) {
@@ -1195,3 +1208,34 @@ status:
 		}
 	})
 }
+
+func TestH2UpgradeEnabled(t *testing.T) {
+	testCases := []struct {
+		annotations      map[string]string
+		enabledByDefault bool
+		expected         bool
+	}{
+		{nil, true, true},
+		{nil, false, false},
+		{map[string]string{pkgK8s.EnableH2UpgradeAnnotation: "false"}, true, false},
+		{map[string]string{pkgK8s.EnableH2UpgradeAnnotation: "true"}, false, true},
+		{map[string]string{pkgK8s.EnableH2UpgradeAnnotation: "nope"}, true, true},
+	}
+
+	for i, tc := range testCases {
+		tc := tc // pin
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "name1",
+					Namespace:   "ns",
+					Annotations: tc.annotations,
+				},
+			}
+			enabled := h2UpgradeEnabled(svc, tc.enabledByDefault, logging.WithFields(nil))
+			if enabled != tc.expected {
+				t.Fatalf("Expected H2 upgrade enabled to be %t, got %t", tc.expected, enabled)
+			}
+		})
+	}
+}
@@ -403,6 +403,13 @@ func (ew *EndpointsWatcher) getOrNewServicePublisher(id ServiceID) *servicePubli
 	return sp
 }
 
+// ServiceCount returns the number of services the watcher currently tracks
+func (ew *EndpointsWatcher) ServiceCount() int {
+	ew.RLock()
+	defer ew.RUnlock()
+	return len(ew.publishers)
+}
+
 func (ew *EndpointsWatcher) getServicePublisher(id ServiceID) (sp *servicePublisher, ok bool) {
 	ew.RLock()
 	defer ew.RUnlock()
@@ -3,10 +3,13 @@ package destination
 import (
 	"context"
 	"flag"
+	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/linkerd/linkerd2/controller/api/destination"
 	"github.com/linkerd/linkerd2/controller/k8s"
@@ -16,6 +19,9 @@ import (
 	"github.com/linkerd/linkerd2/pkg/trace"
 	"github.com/linkerd/linkerd2/pkg/util"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 // Main executes the destination subcommand
@@ -25,18 +31,35 @@ func Main(args []string) {
 	addr := cmd.String("addr", ":8086", "address to serve on")
 	metricsAddr := cmd.String("metrics-addr", ":9996", "address to serve scrapable metrics on")
 	kubeConfigPath := cmd.String("kubeconfig", "", "path to kube config")
-	enableH2Upgrade := cmd.Bool("enable-h2-upgrade", true, "Enable transparently upgraded HTTP2 connections among pods in the service mesh")
+	enableH2Upgrade := cmd.Bool("enable-h2-upgrade", true, "Enable transparently upgraded HTTP2 connections among pods in the service mesh; services can override this with the config.linkerd.io/enable-h2-upgrade annotation")
 	disableIdentity := cmd.Bool("disable-identity", false, "Disable identity configuration")
 	controllerNamespace := cmd.String("controller-namespace", "linkerd", "namespace in which Linkerd is installed")
 	enableEndpointSlices := cmd.Bool("enable-endpoint-slices", false, "Enable the usage of EndpointSlice informers and resources")
 	trustDomain := cmd.String("identity-trust-domain", "", "configures the name suffix used for identities")
 	clusterDomain := cmd.String("cluster-domain", "", "kubernetes cluster domain")
+	validateClusterDomain := cmd.Bool("validate-cluster-domain", true, "Check at startup that the cluster domain is served by the cluster DNS, and warn otherwise")
 	defaultOpaquePorts := cmd.String("default-opaque-ports", "", "configures the default opaque ports")
+	enableGRPCReflection := cmd.Bool("enable-grpc-reflection", false, "Enable gRPC server reflection, to allow listing and calling the destination API with tools like grpcurl")
+	informerResync := cmd.Duration("informer-resync", k8s.DefaultResyncPeriod, "period at which the Kubernetes informers resync their caches; shorter periods recover faster from missed events, at the cost of more load on the Kubernetes API")
+	shutdownGracePeriod := cmd.Duration("shutdown-grace-period", 25*time.Second, "maximum time to wait for in-flight streams to complete on shutdown before forcefully stopping the gRPC server")
 
 	traceCollector := flags.AddTraceFlags(cmd)
+	traceBackend := cmd.String("trace-backend", trace.BackendOC, "kind of trace collector to export to, must be one of: oc, jaeger")
+	traceCollectorTLSCA := cmd.String("trace-collector-tls-ca", "", "path to a CA certificate used to verify the trace collector over TLS; the connection is insecure if unset. Not supported by the jaeger backend")
+	traceSamplingAddr := cmd.String("trace-sampling-addr", "", "loopback address to serve the endpoint changing the trace sampling probability on, through a port-forward; disabled if empty, or if -trace-collector isn't set")
 
 	flags.ConfigureAndParse(cmd, args)
 
+	if err := k8s.ValidateResyncPeriod(*informerResync); err != nil {
+		log.Fatalf("Invalid -informer-resync: %s", err)
+	}
+
+	if *traceSamplingAddr != "" {
+		if err := admin.ValidateLoopbackAddr(*traceSamplingAddr); err != nil {
+			log.Fatalf("Invalid -trace-sampling-addr: %s", err)
+		}
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
@@ -69,8 +92,19 @@ func Main(args []string) {
 	log.Infof("Using default opaque ports: %v", opaquePorts)
 
 	if *traceCollector != "" {
-		if err := trace.InitializeTracing("linkerd-destination", *traceCollector); err != nil {
+		if err := trace.InitializeTracingWithOptions(trace.Options{
+			ServiceName: "linkerd-destination",
+			Address:     *traceCollector,
+			TLSCAPath:   *traceCollectorTLSCA,
+			Backend:     *traceBackend,
+		}); err != nil {
 			log.Warnf("failed to initialize tracing: %s", err)
+		} else if *traceSamplingAddr != "" {
+			// the sampling endpoint isn't served by the admin server, which
+			// is reachable by anything that can reach the pod
+			go admin.StartLoopbackServer(*traceSamplingAddr, map[string]http.Handler{
+				"/tracing/sampling": trace.SamplingHandler(),
+			})
 		}
 	}
 
@@ -83,6 +117,15 @@ func Main(args []string) {
 
 	ctx := context.Background()
 
+	if *validateClusterDomain {
+		// the check only warns, so it doesn't need to delay the startup
+		go func() {
+			if err := checkClusterDomain(ctx, net.DefaultResolver, *clusterDomain); err != nil {
+				log.Warnf("The configured cluster domain %q doesn't seem to be served by the cluster DNS, destinations may be resolved with the wrong authorities: %s", *clusterDomain, err)
+			}
+		}()
+	}
+
 	err = pkgK8s.EndpointSliceAccess(ctx, k8Client)
 	if *enableEndpointSlices && err != nil {
 		log.Fatalf("Failed to start with EndpointSlices enabled: %s", err)
@@ -90,17 +133,19 @@ func Main(args []string) {
 
 	var k8sAPI *k8s.API
 	if *enableEndpointSlices {
-		k8sAPI, err = k8s.InitializeAPI(
+		k8sAPI, err = k8s.InitializeAPIWithResync(
 			ctx,
 			*kubeConfigPath,
 			true,
+			*informerResync,
 			k8s.Endpoint, k8s.ES, k8s.Pod, k8s.RS, k8s.Svc, k8s.SP, k8s.TS, k8s.Job, k8s.NS, k8s.Node,
 		)
 	} else {
-		k8sAPI, err = k8s.InitializeAPI(
+		k8sAPI, err = k8s.InitializeAPIWithResync(
 			ctx,
 			*kubeConfigPath,
 			true,
+			*informerResync,
 			k8s.Endpoint, k8s.Pod, k8s.RS, k8s.Svc, k8s.SP, k8s.TS, k8s.Job, k8s.NS, k8s.Node,
 		)
 	}
@@ -108,7 +153,7 @@ func Main(args []string) {
 		log.Fatalf("Failed to initialize K8s API: %s", err)
 	}
 
-	server, err := destination.NewServer(
+	server, selfCheck, err := destination.NewServer(
 		*addr,
 		*controllerNamespace,
 		*trustDomain,
@@ -124,6 +169,24 @@ func Main(args []string) {
 		log.Fatalf("Failed to initialize destination server: %s", err)
 	}
 
+	// Serve the standard gRPC health protocol, so that the destination service
+	// can be probed with tools like grpc_health_probe. It only reports SERVING
+	// once the caches are synced.
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	if *enableGRPCReflection {
+		log.Info("gRPC server reflection is enabled")
+		reflection.Register(server)
+	}
+
+	readiness := &admin.Readiness{}
+	go admin.StartServerWithHandlers(*metricsAddr, map[string]http.Handler{
+		"/ready":                  readiness,
+		destination.SelfCheckPath: selfCheck,
+	})
+
 	k8sAPI.Sync(nil) // blocks until caches are synced
 
 	go func() {
@@ -131,11 +194,40 @@ func Main(args []string) {
 		server.Serve(lis)
 	}()
 
-	go admin.StartServer(*metricsAddr)
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	readiness.SetReady()
 
 	<-stop
 
 	log.Infof("shutting down gRPC server on %s", *addr)
 	close(done)
-	server.GracefulStop()
+	healthServer.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		log.Info("gRPC server stopped gracefully")
+	case <-time.After(*shutdownGracePeriod):
+		log.Warnf("gRPC server failed to stop gracefully within %s; forcing stop", *shutdownGracePeriod)
+		server.Stop()
+	}
+
+	// send the spans of the last requests served
+	trace.Flush()
+}
+
+// checkClusterDomain resolves the kubernetes API service under clusterDomain,
+// which fails if clusterDomain isn't the domain served by the cluster DNS
+func checkClusterDomain(ctx context.Context, resolver *net.Resolver, clusterDomain string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// The trailing dot prevents the resolver from applying search domains
+	_, err := resolver.LookupHost(ctx, fmt.Sprintf("kubernetes.default.svc.%s.", clusterDomain))
+	return err
 }
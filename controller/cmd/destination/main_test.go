@@ -0,0 +1,100 @@
+package destination
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// stubResolver returns a resolver answering queries itself, with an A record
+// for host and NXDOMAIN for any other name
+func stubResolver(t *testing.T, host string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			// a net.Pipe isn't a PacketConn, so the resolver uses the TCP
+			// framing of DNS messages over it
+			client, server := net.Pipe()
+			go serveDNS(t, server, host)
+			return client, nil
+		},
+	}
+}
+
+func serveDNS(t *testing.T, conn net.Conn, host string) {
+	defer conn.Close()
+	for {
+		var length uint16
+		if err := binary.Read(conn, binary.BigEndian, &length); err != nil {
+			return
+		}
+		query := make([]byte, length)
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+
+		var msg dnsmessage.Message
+		if err := msg.Unpack(query); err != nil {
+			t.Errorf("Failed to parse DNS query: %s", err)
+			return
+		}
+
+		rsp := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: msg.ID, Response: true, Authoritative: true},
+			Questions: msg.Questions,
+		}
+		for _, q := range msg.Questions {
+			if q.Name.String() != host {
+				rsp.RCode = dnsmessage.RCodeNameError
+				continue
+			}
+			if q.Type == dnsmessage.TypeA {
+				rsp.Answers = append(rsp.Answers, dnsmessage.Resource{
+					Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET},
+					Body:   &dnsmessage.AResource{A: [4]byte{10, 96, 0, 1}},
+				})
+			}
+		}
+
+		packed, err := rsp.Pack()
+		if err != nil {
+			t.Errorf("Failed to pack DNS response: %s", err)
+			return
+		}
+		if err := binary.Write(conn, binary.BigEndian, uint16(len(packed))); err != nil {
+			return
+		}
+		if _, err := conn.Write(packed); err != nil {
+			return
+		}
+	}
+}
+
+func TestCheckClusterDomain(t *testing.T) {
+	resolver := stubResolver(t, "kubernetes.default.svc.cluster.local.")
+
+	testCases := []struct {
+		clusterDomain string
+		valid         bool
+	}{
+		{"cluster.local", true},
+		{"example.org", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.clusterDomain, func(t *testing.T) {
+			err := checkClusterDomain(context.Background(), resolver, tc.clusterDomain)
+			if tc.valid && err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatalf("Expected cluster domain %s to be rejected", tc.clusterDomain)
+			}
+		})
+	}
+}
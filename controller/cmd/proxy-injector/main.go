@@ -4,11 +4,15 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 
 	"github.com/linkerd/linkerd2/controller/k8s"
 	injector "github.com/linkerd/linkerd2/controller/proxy-injector"
 	"github.com/linkerd/linkerd2/controller/webhook"
+	"github.com/linkerd/linkerd2/pkg/admin"
 	"github.com/linkerd/linkerd2/pkg/flags"
+	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	log "github.com/sirupsen/logrus"
 )
 
 // Main executes the proxy-injector subcommand
@@ -17,9 +21,22 @@ func Main(args []string) {
 	metricsAddr := cmd.String("metrics-addr", fmt.Sprintf(":%d", 9995), "address to serve scrapable metrics on")
 	addr := cmd.String("addr", ":8443", "address to serve on")
 	kubeconfig := cmd.String("kubeconfig", "", "path to kubeconfig")
+	failurePolicyAddr := cmd.String("failure-policy-addr", "", "loopback address to serve the endpoint changing the webhook failure policy on, through a port-forward; disabled if empty")
+	dryRunAddr := cmd.String("dry-run-addr", "", "loopback address to serve the injection dry-run endpoint on, through a port-forward; disabled if empty")
 	flags.ConfigureAndParse(cmd, args)
 
-	webhook.Launch(
+	if *failurePolicyAddr != "" {
+		if err := admin.ValidateLoopbackAddr(*failurePolicyAddr); err != nil {
+			log.Fatalf("invalid -failure-policy-addr: %s", err)
+		}
+	}
+	if *dryRunAddr != "" {
+		if err := admin.ValidateLoopbackAddr(*dryRunAddr); err != nil {
+			log.Fatalf("invalid -dry-run-addr: %s", err)
+		}
+	}
+
+	webhook.LaunchWithAdminHandlers(
 		context.Background(),
 		[]k8s.APIResource{k8s.NS, k8s.Deploy, k8s.RC, k8s.RS, k8s.Job, k8s.DS, k8s.SS, k8s.Pod, k8s.CJ},
 		injector.Inject,
@@ -27,5 +44,18 @@ func Main(args []string) {
 		*metricsAddr,
 		*addr,
 		*kubeconfig,
+		func(api *k8s.API) map[string]http.Handler {
+			// these endpoints aren't served by the admin server, which is
+			// reachable by anything that can reach the pod
+			if *failurePolicyAddr != "" {
+				go webhook.ServeFailurePolicy(*failurePolicyAddr, api.Client, pkgK8s.ProxyInjectorWebhookConfigName)
+			}
+			if *dryRunAddr != "" {
+				go admin.StartLoopbackServer(*dryRunAddr, map[string]http.Handler{
+					"/inject/dry-run": injector.DryRunHandler(api),
+				})
+			}
+			return nil
+		},
 	)
 }
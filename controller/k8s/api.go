@@ -97,8 +97,34 @@ type API struct {
 	gauges []prometheus.GaugeFunc
 }
 
+const (
+	// DefaultResyncPeriod is the default period at which the informers
+	// resync their caches
+	DefaultResyncPeriod = 10 * time.Minute
+
+	// minResyncPeriod and maxResyncPeriod bound the resync period accepted
+	// by ValidateResyncPeriod
+	minResyncPeriod = 30 * time.Second
+	maxResyncPeriod = 24 * time.Hour
+)
+
+// ValidateResyncPeriod returns an error if resync is too short, putting
+// undue load on the Kubernetes API, or too long to recover from missed events
+func ValidateResyncPeriod(resync time.Duration) error {
+	if resync < minResyncPeriod || resync > maxResyncPeriod {
+		return fmt.Errorf("the informer resync period must be between %s and %s, got %s", minResyncPeriod, maxResyncPeriod, resync)
+	}
+	return nil
+}
+
 // InitializeAPI creates Kubernetes clients and returns an initialized API wrapper.
 func InitializeAPI(ctx context.Context, kubeConfig string, ensureClusterWideAccess bool, resources ...APIResource) (*API, error) {
+	return InitializeAPIWithResync(ctx, kubeConfig, ensureClusterWideAccess, DefaultResyncPeriod, resources...)
+}
+
+// InitializeAPIWithResync creates Kubernetes clients and returns an
+// initialized API wrapper, whose informers resync every resync period.
+func InitializeAPIWithResync(ctx context.Context, kubeConfig string, ensureClusterWideAccess bool, resync time.Duration, resources ...APIResource) (*API, error) {
 	config, err := k8s.GetConfig(kubeConfig, "")
 	if err != nil {
 		return nil, fmt.Errorf("error configuring Kubernetes API client: %v", err)
@@ -109,7 +135,7 @@ func InitializeAPI(ctx context.Context, kubeConfig string, ensureClusterWideAcce
 		return nil, err
 	}
 
-	return initAPI(ctx, k8sClient, config, ensureClusterWideAccess, resources...)
+	return initAPI(ctx, k8sClient, config, ensureClusterWideAccess, resync, resources...)
 }
 
 // InitializeAPIForConfig creates Kubernetes clients and returns an initialized API wrapper.
@@ -119,10 +145,10 @@ func InitializeAPIForConfig(ctx context.Context, kubeConfig *rest.Config, ensure
 		return nil, err
 	}
 
-	return initAPI(ctx, k8sClient, kubeConfig, ensureClusterWideAccess, resources...)
+	return initAPI(ctx, k8sClient, kubeConfig, ensureClusterWideAccess, DefaultResyncPeriod, resources...)
 }
 
-func initAPI(ctx context.Context, k8sClient *k8s.KubernetesAPI, kubeConfig *rest.Config, ensureClusterWideAccess bool, resources ...APIResource) (*API, error) {
+func initAPI(ctx context.Context, k8sClient *k8s.KubernetesAPI, kubeConfig *rest.Config, ensureClusterWideAccess bool, resync time.Duration, resources ...APIResource) (*API, error) {
 	// check for cluster-wide access
 	var err error
 
@@ -164,7 +190,7 @@ func initAPI(ctx context.Context, k8sClient *k8s.KubernetesAPI, kubeConfig *rest
 		}
 	}
 
-	api := NewAPI(k8sClient, spClient, tsClient, resources...)
+	api := NewAPIWithResync(k8sClient, spClient, tsClient, resync, resources...)
 	for _, gauge := range api.gauges {
 		prometheus.Register(gauge)
 	}
@@ -178,16 +204,28 @@ func NewAPI(
 	tsClient tsclient.Interface,
 	resources ...APIResource,
 ) *API {
-	sharedInformers := informers.NewSharedInformerFactory(k8sClient, 10*time.Minute)
+	return NewAPIWithResync(k8sClient, spClient, tsClient, DefaultResyncPeriod, resources...)
+}
+
+// NewAPIWithResync takes a Kubernetes client and returns an initialized API,
+// whose informers resync every resync period.
+func NewAPIWithResync(
+	k8sClient kubernetes.Interface,
+	spClient spclient.Interface,
+	tsClient tsclient.Interface,
+	resync time.Duration,
+	resources ...APIResource,
+) *API {
+	sharedInformers := informers.NewSharedInformerFactory(k8sClient, resync)
 
 	var spSharedInformers sp.SharedInformerFactory
 	if spClient != nil {
-		spSharedInformers = sp.NewSharedInformerFactory(spClient, 10*time.Minute)
+		spSharedInformers = sp.NewSharedInformerFactory(spClient, resync)
 	}
 
 	var tsSharedInformers ts.SharedInformerFactory
 	if tsClient != nil {
-		tsSharedInformers = ts.NewSharedInformerFactory(tsClient, 10*time.Minute)
+		tsSharedInformers = ts.NewSharedInformerFactory(tsClient, resync)
 	}
 
 	api := &API{
@@ -305,6 +343,17 @@ func (api *API) Sync(stopCh <-chan struct{}) {
 	log.Infof("caches synced")
 }
 
+// HasSynced returns true once the caches of all the informers the API was
+// created with are synced
+func (api *API) HasSynced() bool {
+	for _, synced := range api.syncChecks {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
+
 // NS provides access to a shared informer and lister for Namespaces.
 func (api *API) NS() coreinformers.NamespaceInformer {
 	if api.ns == nil {
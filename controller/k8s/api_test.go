@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/labels"
 
@@ -1357,3 +1358,30 @@ spec:
 
 	})
 }
+
+func TestValidateResyncPeriod(t *testing.T) {
+	testCases := []struct {
+		resync time.Duration
+		valid  bool
+	}{
+		{DefaultResyncPeriod, true},
+		{minResyncPeriod, true},
+		{maxResyncPeriod, true},
+		{0, false},
+		{time.Second, false},
+		{48 * time.Hour, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.resync.String(), func(t *testing.T) {
+			err := ValidateResyncPeriod(tc.resync)
+			if tc.valid && err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatalf("Expected an error for %s", tc.resync)
+			}
+		})
+	}
+}
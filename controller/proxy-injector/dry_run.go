@@ -0,0 +1,92 @@
+package injector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/linkerd/linkerd2/pkg/inject"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/yaml"
+)
+
+// dryRunMaxBodySize is the maximum size in bytes of the manifests accepted by
+// DryRunHandler, which is plenty for a pod
+const dryRunMaxBodySize = 4 << 20
+
+// dryRunResult is the outcome of injecting a pod in dry-run mode
+type dryRunResult struct {
+	Injected bool            `json:"injected"`
+	Reasons  []string        `json:"reasons,omitempty"`
+	Patch    json.RawMessage `json:"patch,omitempty"`
+}
+
+// DryRunHandler returns an http.Handler that runs the injection logic on the
+// pod manifest (YAML or JSON) POSTed to it, and responds with whether the pod
+// would be injected, the reasons it wouldn't, and the JSON patch that would
+// be applied to it. Nothing is admitted, and neither events nor admission
+// metrics are recorded.
+func DryRunHandler(api *k8s.API) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		manifest, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, dryRunMaxBodySize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		podJSON, err := yaml.YAMLToJSON(manifest)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid pod manifest: %s", err), http.StatusBadRequest)
+			return
+		}
+		var pod corev1.Pod
+		if err := json.Unmarshal(podJSON, &pod); err != nil {
+			http.Error(w, fmt.Sprintf("invalid pod manifest: %s", err), http.StatusBadRequest)
+			return
+		}
+		if pod.Kind != "Pod" {
+			http.Error(w, fmt.Sprintf("expected a Pod manifest, got %q", pod.Kind), http.StatusBadRequest)
+			return
+		}
+		namespace := pod.Namespace
+		if namespace == "" {
+			namespace = corev1.NamespaceDefault
+		}
+
+		request := &admissionv1beta1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			Namespace: namespace,
+			Object:    runtime.RawExtension{Raw: podJSON},
+		}
+		// The FakeRecorder discards events when it has no Events channel
+		response, reasons, err := injectWithReasons(req.Context(), api, request, &record.FakeRecorder{}, false)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result := dryRunResult{
+			Injected: len(reasons) == 0,
+			Patch:    response.Patch,
+		}
+		for _, reason := range reasons {
+			result.Reasons = append(result.Reasons, inject.Reasons[reason])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
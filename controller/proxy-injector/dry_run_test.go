@@ -0,0 +1,209 @@
+package injector
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/linkerd/linkerd2/controller/k8s"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestDryRunHandler(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		name         string
+		method       string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "rejects GET requests",
+			method:       http.MethodGet,
+			expectedCode: http.StatusMethodNotAllowed,
+			expectedBody: "method not allowed\n",
+		},
+		{
+			name:         "rejects invalid manifests",
+			method:       http.MethodPost,
+			body:         "kind: [Pod",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "invalid pod manifest",
+		},
+		{
+			name:         "rejects oversized manifests",
+			method:       http.MethodPost,
+			body:         "kind: Pod\nmetadata:\n  name: " + strings.Repeat("a", dryRunMaxBodySize),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "http: request body too large\n",
+		},
+		{
+			name:   "rejects manifests other than pods",
+			method: http.MethodPost,
+			body: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "expected a Pod manifest, got \"Deployment\"\n",
+		},
+		{
+			// withValuesConfig isn't called, so the values config is missing
+			name:   "reports injection errors",
+			method: http.MethodPost,
+			body: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: emojivoto`,
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "failed to read",
+		},
+	}
+
+	handler := DryRunHandler(k8sAPI)
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, "/inject/dry-run", strings.NewReader(tc.body)))
+
+			if rec.Code != tc.expectedCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tc.expectedCode, rec.Code, rec.Body.String())
+			}
+			if !strings.HasPrefix(rec.Body.String(), tc.expectedBody) {
+				t.Fatalf("Expected body to start with %q, got %q", tc.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestDryRunHandlerInjection(t *testing.T) {
+	withValuesConfig(t, false)
+
+	k8sAPI, err := k8s.NewFakeAPI(`
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: emojivoto
+  annotations:
+    linkerd.io/inject: enabled`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	k8sAPI.Sync(nil)
+
+	testCases := []struct {
+		name    string
+		body    string
+		checker func(t *testing.T, result dryRunResult)
+	}{
+		{
+			name: "injects pods",
+			body: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: emojivoto
+spec:
+  containers:
+  - name: web
+    image: buoyantio/emojivoto-web:v11
+    volumeMounts:
+    - name: web-token
+      mountPath: /var/run/secrets/kubernetes.io/serviceaccount`,
+			checker: func(t *testing.T, result dryRunResult) {
+				if !result.Injected || len(result.Reasons) != 0 {
+					t.Fatalf("Expected the pod to be injected, got reasons %v", result.Reasons)
+				}
+				if !strings.Contains(string(result.Patch), `"name":"linkerd-proxy"`) {
+					t.Fatalf("Expected the patch to add the proxy container, got %s", result.Patch)
+				}
+			},
+		},
+		{
+			name: "reports skip reasons",
+			body: `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: emojivoto
+  annotations:
+    linkerd.io/inject: disabled
+spec:
+  containers:
+  - name: web
+    image: buoyantio/emojivoto-web:v11
+    volumeMounts:
+    - name: web-token
+      mountPath: /var/run/secrets/kubernetes.io/serviceaccount`,
+			checker: func(t *testing.T, result dryRunResult) {
+				expected := []string{`pod has the annotation "linkerd.io/inject:disabled"`}
+				if result.Injected || !reflect.DeepEqual(result.Reasons, expected) {
+					t.Fatalf("Expected the pod to be skipped with reasons %v, got %v", expected, result.Reasons)
+				}
+				if len(result.Patch) != 0 {
+					t.Fatalf("Expected no patch, got %s", result.Patch)
+				}
+			},
+		},
+	}
+
+	handler := DryRunHandler(k8sAPI)
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.name, func(t *testing.T) {
+			before := admissionCounts(t)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/inject/dry-run", strings.NewReader(tc.body)))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+			}
+
+			var result dryRunResult
+			if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			tc.checker(t, result)
+
+			// dry runs don't record admission metrics
+			if after := admissionCounts(t); !reflect.DeepEqual(before, after) {
+				t.Fatalf("Expected the admission metrics to be unchanged, went from %v to %v", before, after)
+			}
+		})
+	}
+}
+
+// admissionCounts returns the number of series of each admission counter
+// along with their total
+func admissionCounts(t *testing.T) map[string]float64 {
+	counts := map[string]float64{}
+	for name, counter := range map[string]*prometheus.CounterVec{
+		"requests":  proxyInjectionAdmissionRequests,
+		"responses": proxyInjectionAdmissionResponses,
+		"errors":    proxyInjectionAdmissionErrors,
+	} {
+		ch := make(chan prometheus.Metric, 100)
+		counter.Collect(ch)
+		close(ch)
+		for m := range ch {
+			var metric dto.Metric
+			if err := m.Write(&metric); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			counts[name+"_series"]++
+			counts[name+"_total"] += metric.GetCounter().GetValue()
+		}
+	}
+	return counts
+}
@@ -15,6 +15,14 @@ const (
 	labelSkip         = "skip"
 	labelAnnotationAt = "annotation_at"
 	labelReason       = "skip_reason"
+	labelErrorReason  = "error_reason"
+
+	// Reasons for failing to handle an admission request, reported with
+	// proxyInjectionAdmissionErrors
+	errorReasonReadConfig    = "read_config"
+	errorReasonGetNamespace  = "get_namespace"
+	errorReasonParseResource = "parse_resource"
+	errorReasonCreatePatch   = "create_patch"
 )
 
 var (
@@ -30,6 +38,11 @@ var (
 		Name: "proxy_inject_admission_responses_total",
 		Help: "A counter for number of admission responses from proxy injector.",
 	}, append(responseLabels, validLabelNames(inject.ProxyAnnotations)...))
+
+	proxyInjectionAdmissionErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "proxy_inject_admission_errors_total",
+		Help: "A counter for number of admission requests the proxy injector failed to handle, leaving the resource uninjected.",
+	}, []string{labelNamespace, labelErrorReason})
 )
 
 func admissionRequestLabels(ownerKind, namespace, annotationAt string, configLabels prometheus.Labels) prometheus.Labels {
@@ -48,6 +61,13 @@ func admissionResponseLabels(owner, namespace, skip, reason, annotationAt string
 	return configLabels
 }
 
+func admissionErrorLabels(namespace, reason string) prometheus.Labels {
+	return prometheus.Labels{
+		labelNamespace:   namespace,
+		labelErrorReason: reason,
+	}
+}
+
 func configToPrometheusLabels(conf *inject.ResourceConfig) prometheus.Labels {
 	labels := conf.GetOverriddenConfiguration()
 	promLabels := map[string]string{}
@@ -23,6 +23,10 @@ const (
 	eventTypeInjected = "Injected"
 )
 
+// valuesConfigPath is the path of the values config file read on each
+// request, overridden in tests
+var valuesConfigPath = pkgK8s.MountPathValuesConfig
+
 // Inject returns an AdmissionResponse containing the patch, if any, to apply
 // to the pod (proxy sidecar and eventually the init container to set it up)
 func Inject(
@@ -31,18 +35,38 @@ func Inject(
 	request *admissionv1beta1.AdmissionRequest,
 	recorder record.EventRecorder,
 ) (*admissionv1beta1.AdmissionResponse, error) {
+	response, _, err := injectWithReasons(ctx, api, request, recorder, true)
+	return response, err
+}
+
+// injectWithReasons implements Inject, additionally returning the reasons why
+// the resource wasn't injected, which are empty if it was. Admission metrics
+// are only updated if recordMetrics is true.
+func injectWithReasons(
+	ctx context.Context,
+	api *k8s.API,
+	request *admissionv1beta1.AdmissionRequest,
+	recorder record.EventRecorder,
+	recordMetrics bool,
+) (*admissionv1beta1.AdmissionResponse, []string, error) {
 	log.Debugf("request object bytes: %s", request.Object.Raw)
 
 	// Build the resource config based off the request metadata and kind of
 	// object. This is later used to build the injection report and generated
 	// patch.
-	valuesConfig, err := config.Values(pkgK8s.MountPathValuesConfig)
+	valuesConfig, err := config.Values(valuesConfigPath)
 	if err != nil {
-		return nil, err
+		if recordMetrics {
+			proxyInjectionAdmissionErrors.With(admissionErrorLabels(request.Namespace, errorReasonReadConfig)).Inc()
+		}
+		return nil, nil, err
 	}
 	namespace, err := api.NS().Lister().Get(request.Namespace)
 	if err != nil {
-		return nil, err
+		if recordMetrics {
+			proxyInjectionAdmissionErrors.With(admissionErrorLabels(request.Namespace, errorReasonGetNamespace)).Inc()
+		}
+		return nil, nil, err
 	}
 	nsAnnotations := namespace.GetAnnotations()
 	resourceConfig := inject.NewResourceConfig(valuesConfig, inject.OriginWebhook).
@@ -53,7 +77,10 @@ func Inject(
 	// Build the injection report.
 	report, err := resourceConfig.ParseMetaAndYAML(request.Object.Raw)
 	if err != nil {
-		return nil, err
+		if recordMetrics {
+			proxyInjectionAdmissionErrors.With(admissionErrorLabels(request.Namespace, errorReasonParseResource)).Inc()
+		}
+		return nil, nil, err
 	}
 	log.Infof("received %s", report.ResName())
 
@@ -74,7 +101,9 @@ func Inject(
 	}
 
 	configLabels := configToPrometheusLabels(resourceConfig)
-	proxyInjectionAdmissionRequests.With(admissionRequestLabels(ownerKind, request.Namespace, report.InjectAnnotationAt, configLabels)).Inc()
+	if recordMetrics {
+		proxyInjectionAdmissionRequests.With(admissionRequestLabels(ownerKind, request.Namespace, report.InjectAnnotationAt, configLabels)).Inc()
+	}
 
 	// If the resource is injectable then admit it after creating a patch that
 	// adds the proxy-init and proxy containers.
@@ -87,21 +116,26 @@ func Inject(
 		resourceConfig.AppendNamespaceAnnotations()
 		patchJSON, err := resourceConfig.GetPodPatch(true)
 		if err != nil {
-			return nil, err
+			if recordMetrics {
+				proxyInjectionAdmissionErrors.With(admissionErrorLabels(request.Namespace, errorReasonCreatePatch)).Inc()
+			}
+			return nil, nil, err
 		}
 		if parent != nil {
 			recorder.Event(*parent, v1.EventTypeNormal, eventTypeInjected, "Linkerd sidecar proxy injected")
 		}
 		log.Infof("injection patch generated for: %s", report.ResName())
 		log.Debugf("injection patch: %s", patchJSON)
-		proxyInjectionAdmissionResponses.With(admissionResponseLabels(ownerKind, request.Namespace, "false", "", report.InjectAnnotationAt, configLabels)).Inc()
+		if recordMetrics {
+			proxyInjectionAdmissionResponses.With(admissionResponseLabels(ownerKind, request.Namespace, "false", "", report.InjectAnnotationAt, configLabels)).Inc()
+		}
 		patchType := admissionv1beta1.PatchTypeJSONPatch
 		return &admissionv1beta1.AdmissionResponse{
 			UID:       request.UID,
 			Allowed:   true,
 			PatchType: &patchType,
 			Patch:     patchJSON,
-		}, nil
+		}, nil, nil
 	}
 
 	// If the resource is not injectable but does need the opaque ports
@@ -110,18 +144,23 @@ func Inject(
 	if opaquePorts, opaquePortsOk := resourceConfig.GetConfigAnnotation(pkgK8s.ProxyOpaquePortsAnnotation); opaquePortsOk {
 		patchJSON, err := resourceConfig.CreateAnnotationPatch(opaquePorts)
 		if err != nil {
-			return nil, err
+			if recordMetrics {
+				proxyInjectionAdmissionErrors.With(admissionErrorLabels(request.Namespace, errorReasonCreatePatch)).Inc()
+			}
+			return nil, nil, err
 		}
 		log.Infof("annotation patch generated for: %s", report.ResName())
 		log.Debugf("annotation patch: %s", patchJSON)
-		proxyInjectionAdmissionResponses.With(admissionResponseLabels(ownerKind, request.Namespace, "false", "", report.InjectAnnotationAt, configLabels)).Inc()
+		if recordMetrics {
+			proxyInjectionAdmissionResponses.With(admissionResponseLabels(ownerKind, request.Namespace, "false", "", report.InjectAnnotationAt, configLabels)).Inc()
+		}
 		patchType := admissionv1beta1.PatchTypeJSONPatch
 		return &admissionv1beta1.AdmissionResponse{
 			UID:       request.UID,
 			Allowed:   true,
 			PatchType: &patchType,
 			Patch:     patchJSON,
-		}, nil
+		}, reasons, nil
 	}
 
 	// The resource should be admitted without a patch. If it is a pod, create
@@ -138,16 +177,18 @@ func Inject(
 			recorder.Eventf(*parent, v1.EventTypeNormal, eventTypeSkipped, "Linkerd sidecar proxy injection skipped: %s", readableReasons)
 		}
 		log.Infof("skipped %s: %s", report.ResName(), readableReasons)
-		proxyInjectionAdmissionResponses.With(admissionResponseLabels(ownerKind, request.Namespace, "true", metricReasons, report.InjectAnnotationAt, configLabels)).Inc()
+		if recordMetrics {
+			proxyInjectionAdmissionResponses.With(admissionResponseLabels(ownerKind, request.Namespace, "true", metricReasons, report.InjectAnnotationAt, configLabels)).Inc()
+		}
 		return &admissionv1beta1.AdmissionResponse{
 			UID:     request.UID,
 			Allowed: true,
-		}, nil
+		}, reasons, nil
 	}
 	return &admissionv1beta1.AdmissionResponse{
 		UID:     request.UID,
 		Allowed: true,
-	}, nil
+	}, reasons, nil
 }
 
 func ownerRetriever(ctx context.Context, api *k8s.API, ns string) inject.OwnerRetrieverFunc {
@@ -1,21 +1,27 @@
 package injector
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"reflect"
 	"testing"
 
+	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/controller/proxy-injector/fake"
 	"github.com/linkerd/linkerd2/pkg/charts/linkerd2"
 	"github.com/linkerd/linkerd2/pkg/inject"
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
 )
 
 type unmarshalledPatch []map[string]interface{}
@@ -398,3 +404,66 @@ func unmarshalPatch(patchJSON []byte) (unmarshalledPatch, error) {
 
 	return actualPatch, nil
 }
+
+// withValuesConfig points the injector at a values config file holding the
+// default values and test trust anchors, or at a missing file if missing is true, for the duration
+// of the test
+func withValuesConfig(t *testing.T, missing bool) {
+	path := filepath.Join(t.TempDir(), "values")
+	if !missing {
+		values, err := linkerd2.NewValues()
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		// set by install, and required to render the proxy
+		values.IdentityTrustAnchorsPEM = "test-trust-anchors"
+		valuesYaml, err := yaml.Marshal(values)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := ioutil.WriteFile(path, valuesYaml, 0600); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	previous := valuesConfigPath
+	valuesConfigPath = path
+	t.Cleanup(func() { valuesConfigPath = previous })
+}
+
+func TestInjectCountsErrors(t *testing.T) {
+	k8sAPI, err := k8s.NewFakeAPI()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		missingConfig bool
+		reason        string
+	}{
+		{true, errorReasonReadConfig},
+		// the inject-errors namespace doesn't exist
+		{false, errorReasonGetNamespace},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.reason, func(t *testing.T) {
+			withValuesConfig(t, tc.missingConfig)
+
+			counter := proxyInjectionAdmissionErrors.With(prometheus.Labels{
+				"namespace":    "inject-errors",
+				"error_reason": tc.reason,
+			})
+			before := testutil.ToFloat64(counter)
+			request := &admissionv1beta1.AdmissionRequest{Namespace: "inject-errors"}
+			if _, err := Inject(context.Background(), k8sAPI, request, nil); err == nil {
+				t.Fatal("Expected an error, got nothing")
+			}
+
+			if after := testutil.ToFloat64(counter); after != before+1 {
+				t.Fatalf("Expected %s errors to be counted once, went from %v to %v", tc.reason, before, after)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/linkerd/linkerd2/pkg/admin"
+	log "github.com/sirupsen/logrus"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+type jsonPatchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+// FailurePolicyPath is the path FailurePolicyHandler is served at by
+// ServeFailurePolicy
+const FailurePolicyPath = "/webhook/failure-policy"
+
+// ServeFailurePolicy serves FailurePolicyHandler on addr, which must pass
+// admin.ValidateLoopbackAddr: the endpoint is unauthenticated
+func ServeFailurePolicy(addr string, client kubernetes.Interface, configName string) {
+	admin.StartLoopbackServer(addr, map[string]http.Handler{
+		FailurePolicyPath: FailurePolicyHandler(client, configName),
+	})
+}
+
+// FailurePolicyHandler returns an http.Handler reporting the failure policy
+// of the MutatingWebhookConfiguration named configName. PUT and POST requests
+// patch every webhook in it with the policy given by the "policy" parameter,
+// which must be either Ignore or Fail. This allows injection failures to be
+// ignored during an incident without re-running install.
+func FailurePolicyHandler(client kubernetes.Interface, configName string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mwc, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(req.Context(), configName, metav1.GetOptions{})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			policy := admissionregistrationv1.FailurePolicyType(req.FormValue("policy"))
+			if policy != admissionregistrationv1.Ignore && policy != admissionregistrationv1.Fail {
+				http.Error(w, fmt.Sprintf("invalid policy %q, must be one of: %s, %s", policy, admissionregistrationv1.Ignore, admissionregistrationv1.Fail), http.StatusBadRequest)
+				return
+			}
+
+			patch := make([]jsonPatchOp, len(mwc.Webhooks))
+			for i := range mwc.Webhooks {
+				patch[i] = jsonPatchOp{
+					Op:    "replace",
+					Path:  fmt.Sprintf("/webhooks/%d/failurePolicy", i),
+					Value: string(policy),
+				}
+			}
+			patchJSON, err := json.Marshal(patch)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			mwc, err = client.AdmissionregistrationV1().MutatingWebhookConfigurations().Patch(req.Context(), configName, types.JSONPatchType, patchJSON, metav1.PatchOptions{})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			log.Warnf("failure policy of MutatingWebhookConfiguration %s set to %s", configName, policy)
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		policies := make([]string, len(mwc.Webhooks))
+		for i, webhook := range mwc.Webhooks {
+			if webhook.FailurePolicy != nil {
+				policies[i] = string(*webhook.FailurePolicy)
+			}
+		}
+		fmt.Fprintf(w, "%s\n", strings.Join(policies, ","))
+	})
+}
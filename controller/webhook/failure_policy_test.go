@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestFailurePolicyHandler(t *testing.T) {
+	fail := admissionregistrationv1.Fail
+	client := fake.NewSimpleClientset(&admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "linkerd-proxy-injector-webhook-config"},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{Name: "linkerd-proxy-injector.linkerd.io", FailurePolicy: &fail},
+		},
+	})
+
+	testCases := []struct {
+		method       string
+		url          string
+		expectedCode int
+		expectedBody string
+	}{
+		{http.MethodGet, "/webhook/failure-policy", http.StatusOK, "Fail\n"},
+		{http.MethodPut, "/webhook/failure-policy?policy=Ignore", http.StatusOK, "Ignore\n"},
+		{http.MethodGet, "/webhook/failure-policy", http.StatusOK, "Ignore\n"},
+		{http.MethodPost, "/webhook/failure-policy?policy=Fail", http.StatusOK, "Fail\n"},
+		{http.MethodPut, "/webhook/failure-policy?policy=Maybe", http.StatusBadRequest, "invalid policy \"Maybe\", must be one of: Ignore, Fail\n"},
+		{http.MethodDelete, "/webhook/failure-policy", http.StatusMethodNotAllowed, "method not allowed\n"},
+		{http.MethodGet, "/webhook/failure-policy", http.StatusOK, "Fail\n"},
+	}
+
+	handler := FailurePolicyHandler(client, "linkerd-proxy-injector-webhook-config")
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.method+" "+tc.url, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.url, nil))
+
+			if rec.Code != tc.expectedCode {
+				t.Fatalf("Expected status code %d, got %d", tc.expectedCode, rec.Code)
+			}
+			if rec.Body.String() != tc.expectedBody {
+				t.Fatalf("Expected body %q, got %q", tc.expectedBody, rec.Body.String())
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	FailurePolicyHandler(client, "missing").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook/failure-policy", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status code %d for a missing configuration, got %d", http.StatusInternalServerError, rec.Code)
+	}
+}
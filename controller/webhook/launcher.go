@@ -2,6 +2,7 @@ package webhook
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -22,6 +23,22 @@ func Launch(
 	metricsAddr string,
 	addr string,
 	kubeconfig string,
+) {
+	LaunchWithAdminHandlers(ctx, APIResources, handler, component, metricsAddr, addr, kubeconfig, nil)
+}
+
+// LaunchWithAdminHandlers sets up and starts the webhook and metrics servers,
+// the latter additionally serving the handlers returned by adminHandlers,
+// keyed by URL path
+func LaunchWithAdminHandlers(
+	ctx context.Context,
+	APIResources []k8s.APIResource,
+	handler Handler,
+	component,
+	metricsAddr string,
+	addr string,
+	kubeconfig string,
+	adminHandlers func(*k8s.API) map[string]http.Handler,
 ) {
 	stop := make(chan os.Signal, 1)
 	defer close(stop)
@@ -40,7 +57,11 @@ func Launch(
 	k8sAPI.Sync(nil)
 
 	go s.Start()
-	go admin.StartServer(metricsAddr)
+	var extraHandlers map[string]http.Handler
+	if adminHandlers != nil {
+		extraHandlers = adminHandlers(k8sAPI)
+	}
+	go admin.StartServerWithHandlers(metricsAddr, extraHandlers)
 
 	<-stop
 	log.Info("shutting down webhook server")
@@ -4,13 +4,17 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/linkerd/linkerd2/controller/k8s"
+	pkgTls "github.com/linkerd/linkerd2/pkg/tls"
 )
 
 var mockHTTPServer = &http.Server{
@@ -58,3 +62,47 @@ func TestShutdown(t *testing.T) {
 		t.Fatal("Unexpected error: ", err)
 	}
 }
+
+func TestGetCertificateAfterRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhook-certs")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	crtPath := filepath.Join(dir, "tls.crt")
+	keyPath := filepath.Join(dir, "tls.key")
+
+	writeCert := func() *pkgTls.CA {
+		ca, err := pkgTls.GenerateRootCAWithDefaults("linkerd-proxy-injector.linkerd.svc")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := ioutil.WriteFile(crtPath, []byte(ca.Cred.EncodeCertificatePEM()), 0600); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := ioutil.WriteFile(keyPath, []byte(ca.Cred.EncodePrivateKeyPEM()), 0600); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return ca
+	}
+
+	testServer := getConfiguredServer(&http.Server{TLSConfig: &tls.Config{}}, nil, nil, nil)
+	watcher := pkgTls.NewFsCredsWatcher(dir, nil, nil).WithFilePaths(crtPath, keyPath)
+
+	// The webhook keeps serving the current cert until the mounted secret is
+	// updated, and then serves the new one without being restarted
+	for i := 0; i < 2; i++ {
+		ca := writeCert()
+		if err := watcher.UpdateCert(testServer.certValue); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		cert, err := testServer.getCertificate(nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !bytes.Equal(cert.Certificate[0], ca.Cred.Certificate.Raw) {
+			t.Fatalf("Expected the webhook to serve the certificate written in iteration %d", i)
+		}
+	}
+}
@@ -53,7 +53,7 @@ func NewCmdJaeger() *cobra.Command {
 				return fmt.Errorf("%s is not a valid namespace", controlPlaneNamespace)
 			}
 
-			return nil
+			return pkgcmd.ValidateImpersonation(impersonate, impersonateGroup)
 		},
 	}
 
@@ -68,7 +68,7 @@ components on a cluster, manage credentials and link clusters together.`,
 			} else {
 				log.SetLevel(log.PanicLevel)
 			}
-			return nil
+			return pkgcmd.ValidateImpersonation(impersonate, impersonateGroup)
 		},
 	}
 
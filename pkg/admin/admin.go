@@ -2,36 +2,86 @@ package admin
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/pprof"
 	"strings"
+	"sync/atomic"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 )
 
 type handler struct {
-	promHandler http.Handler
+	promHandler   http.Handler
+	extraHandlers map[string]http.Handler
 }
 
 // StartServer starts an admin server listening on a given address.
 func StartServer(addr string) {
+	StartServerWithHandlers(addr, nil)
+}
+
+// StartServerWithHandlers starts an admin server listening on a given
+// address, additionally serving extraHandlers, keyed by URL path.
+func StartServerWithHandlers(addr string, extraHandlers map[string]http.Handler) {
 	log.Infof("starting admin server on %s", addr)
 
 	h := &handler{
-		promHandler: promhttp.Handler(),
+		promHandler:   promhttp.Handler(),
+		extraHandlers: extraHandlers,
 	}
 
 	log.Fatal(http.ListenAndServe(addr, h))
 }
 
+// ValidateLoopbackAddr checks that addr only listens on the loopback
+// interface. Endpoints changing the state of a process are unauthenticated,
+// so they're only meant to be reached through a port-forward into the pod,
+// and not served by the admin server, which anything reaching the pod can
+// call.
+func ValidateLoopbackAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("address %s must listen on localhost or a loopback IP", addr)
+	}
+	return nil
+}
+
+// StartLoopbackServer serves handlers, keyed by URL path, on addr, which must
+// pass ValidateLoopbackAddr
+func StartLoopbackServer(addr string, handlers map[string]http.Handler) {
+	log.Infof("starting loopback admin server on %s", addr)
+
+	mux := http.NewServeMux()
+	for path, h := range handlers {
+		mux.Handle(path, h)
+	}
+	log.Fatal(http.ListenAndServe(addr, mux))
+}
+
 func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// extra handlers take precedence, allowing callers to override the
+	// default behavior of endpoints such as /ready
+	if extra, ok := h.extraHandlers[req.URL.Path]; ok {
+		extra.ServeHTTP(w, req)
+		return
+	}
+
 	debugPathPrefix := "/debug/pprof/"
 	switch req.URL.Path {
 	case "/metrics":
 		h.promHandler.ServeHTTP(w, req)
 	case "/ping":
 		h.servePing(w)
+	case "/live":
+		h.serveLive(w)
 	case "/ready":
 		h.serveReady(w)
 	case fmt.Sprintf("%scmdline", debugPathPrefix):
@@ -55,6 +105,30 @@ func (h *handler) servePing(w http.ResponseWriter) {
 	w.Write([]byte("pong\n"))
 }
 
+func (h *handler) serveLive(w http.ResponseWriter) {
+	w.Write([]byte("live\n"))
+}
+
 func (h *handler) serveReady(w http.ResponseWriter) {
 	w.Write([]byte("ok\n"))
 }
+
+// Readiness is an http.Handler for the /ready endpoint that responds with an
+// error until SetReady is called.
+type Readiness struct {
+	ready int32
+}
+
+// SetReady marks the process as ready to serve requests
+func (r *Readiness) SetReady() {
+	atomic.StoreInt32(&r.ready, 1)
+}
+
+// ServeHTTP implements http.Handler
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if atomic.LoadInt32(&r.ready) == 0 {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte("ok\n"))
+}
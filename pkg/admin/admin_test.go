@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadiness(t *testing.T) {
+	readiness := &Readiness{}
+	h := &handler{
+		extraHandlers: map[string]http.Handler{"/ready": readiness},
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status code %d before SetReady, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/live", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d for /live, got %d", http.StatusOK, rec.Code)
+	}
+
+	readiness.SetReady()
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d after SetReady, got %d", http.StatusOK, rec.Code)
+	}
+}
+
+func TestValidateLoopbackAddr(t *testing.T) {
+	testCases := []struct {
+		addr  string
+		valid bool
+	}{
+		{"localhost:9997", true},
+		{"127.0.0.1:9997", true},
+		{"[::1]:9997", true},
+		{":9997", false},
+		{"0.0.0.0:9997", false},
+		{"10.0.0.1:9997", false},
+		{"localhost", false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.addr, func(t *testing.T) {
+			err := ValidateLoopbackAddr(tc.addr)
+			if tc.valid && err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatalf("Expected %s to be rejected", tc.addr)
+			}
+		})
+	}
+}
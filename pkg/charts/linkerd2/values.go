@@ -3,6 +3,8 @@ package linkerd2
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/imdario/mergo"
@@ -13,6 +15,7 @@ import (
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/chartutil"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 )
@@ -25,50 +28,52 @@ const (
 type (
 	// Values contains the top-level elements in the Helm charts
 	Values struct {
-		ControllerImage              string              `json:"controllerImage"`
-		ControllerReplicas           uint                `json:"controllerReplicas"`
-		ControllerUID                int64               `json:"controllerUID"`
-		EnableH2Upgrade              bool                `json:"enableH2Upgrade"`
-		EnablePodAntiAffinity        bool                `json:"enablePodAntiAffinity"`
-		WebhookFailurePolicy         string              `json:"webhookFailurePolicy"`
-		OmitWebhookSideEffects       bool                `json:"omitWebhookSideEffects"`
-		DisableHeartBeat             bool                `json:"disableHeartBeat"`
-		HeartbeatSchedule            string              `json:"heartbeatSchedule"`
-		InstallNamespace             bool                `json:"installNamespace"`
-		Configs                      ConfigJSONs         `json:"configs"`
-		Namespace                    string              `json:"namespace"`
-		ClusterDomain                string              `json:"clusterDomain"`
-		ClusterNetworks              string              `json:"clusterNetworks"`
-		ImagePullPolicy              string              `json:"imagePullPolicy"`
-		CliVersion                   string              `json:"cliVersion"`
-		ControllerImageVersion       string              `json:"controllerImageVersion"`
-		ControllerLogLevel           string              `json:"controllerLogLevel"`
-		ControllerLogFormat          string              `json:"controllerLogFormat"`
-		ProxyContainerName           string              `json:"proxyContainerName"`
-		HighAvailability             bool                `json:"highAvailability"`
-		CNIEnabled                   bool                `json:"cniEnabled"`
-		EnableEndpointSlices         bool                `json:"enableEndpointSlices"`
-		ControlPlaneTracing          bool                `json:"controlPlaneTracing"`
-		ControlPlaneTracingNamespace string              `json:"controlPlaneTracingNamespace"`
-		IdentityTrustAnchorsPEM      string              `json:"identityTrustAnchorsPEM"`
-		IdentityTrustDomain          string              `json:"identityTrustDomain"`
-		PrometheusURL                string              `json:"prometheusUrl"`
-		GrafanaURL                   string              `json:"grafanaUrl"`
-		ImagePullSecrets             []map[string]string `json:"imagePullSecrets"`
-		LinkerdVersion               string              `json:"linkerdVersion"`
+		ControllerImage                     string                        `json:"controllerImage"`
+		ControllerReplicas                  uint                          `json:"controllerReplicas"`
+		ControllerUID                       int64                         `json:"controllerUID"`
+		EnableH2Upgrade                     bool                          `json:"enableH2Upgrade"`
+		EnablePodAntiAffinity               bool                          `json:"enablePodAntiAffinity"`
+		WebhookFailurePolicy                string                        `json:"webhookFailurePolicy"`
+		OmitWebhookSideEffects              bool                          `json:"omitWebhookSideEffects"`
+		DisableHeartBeat                    bool                          `json:"disableHeartBeat"`
+		HeartbeatSchedule                   string                        `json:"heartbeatSchedule"`
+		InstallNamespace                    bool                          `json:"installNamespace"`
+		Configs                             ConfigJSONs                   `json:"configs"`
+		Namespace                           string                        `json:"namespace"`
+		ClusterDomain                       string                        `json:"clusterDomain"`
+		ClusterNetworks                     string                        `json:"clusterNetworks"`
+		ImagePullPolicy                     string                        `json:"imagePullPolicy"`
+		CliVersion                          string                        `json:"cliVersion"`
+		ControllerImageVersion              string                        `json:"controllerImageVersion"`
+		ControllerLogLevel                  string                        `json:"controllerLogLevel"`
+		ControllerLogFormat                 string                        `json:"controllerLogFormat"`
+		ProxyContainerName                  string                        `json:"proxyContainerName"`
+		HighAvailability                    bool                          `json:"highAvailability"`
+		CNIEnabled                          bool                          `json:"cniEnabled"`
+		EnableEndpointSlices                bool                          `json:"enableEndpointSlices"`
+		ControlPlaneTracing                 bool                          `json:"controlPlaneTracing"`
+		ControlPlaneTracingNamespace        string                        `json:"controlPlaneTracingNamespace"`
+		ControlPlaneTracingSamplingEndpoint bool                          `json:"controlPlaneTracingSamplingEndpoint"`
+		IdentityTrustAnchorsPEM             string                        `json:"identityTrustAnchorsPEM"`
+		IdentityTrustDomain                 string                        `json:"identityTrustDomain"`
+		PrometheusURL                       string                        `json:"prometheusUrl"`
+		GrafanaURL                          string                        `json:"grafanaUrl"`
+		ImagePullSecrets                    []corev1.LocalObjectReference `json:"imagePullSecrets"`
+		LinkerdVersion                      string                        `json:"linkerdVersion"`
 
 		PodAnnotations map[string]string `json:"podAnnotations"`
 		PodLabels      map[string]string `json:"podLabels"`
 
-		Proxy            *Proxy            `json:"proxy"`
-		ProxyInit        *ProxyInit        `json:"proxyInit"`
-		Identity         *Identity         `json:"identity"`
-		DebugContainer   *DebugContainer   `json:"debugContainer"`
-		ProxyInjector    *ProxyInjector    `json:"proxyInjector"`
-		ProfileValidator *ProfileValidator `json:"profileValidator"`
-		NodeSelector     map[string]string `json:"nodeSelector"`
-		Tolerations      []interface{}     `json:"tolerations"`
-		Stage            string            `json:"stage"`
+		Proxy             *Proxy              `json:"proxy"`
+		ProxyInit         *ProxyInit          `json:"proxyInit"`
+		Identity          *Identity           `json:"identity"`
+		DebugContainer    *DebugContainer     `json:"debugContainer"`
+		ProxyInjector     *ProxyInjector      `json:"proxyInjector"`
+		ProfileValidator  *ProfileValidator   `json:"profileValidator"`
+		NodeSelector      map[string]string   `json:"nodeSelector"`
+		PriorityClassName string              `json:"priorityClassName"`
+		Tolerations       []corev1.Toleration `json:"tolerations"`
+		Stage             string              `json:"stage"`
 
 		DestinationResources   *Resources `json:"destinationResources"`
 		HeartbeatResources     *Resources `json:"heartbeatResources"`
@@ -110,6 +115,7 @@ type (
 		PodInboundPorts               string           `json:"podInboundPorts"`
 		OpaquePorts                   string           `json:"opaquePorts"`
 		Await                         bool             `json:"await"`
+		ExtraEnv                      []corev1.EnvVar  `json:"extraEnv"`
 	}
 
 	// ProxyInit contains the fields to set the proxy-init container
@@ -188,7 +194,9 @@ type (
 	// ProxyInjector has all the proxy injector's Helm variables
 	ProxyInjector struct {
 		*TLS
-		NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector"`
+		NamespaceSelector           *metav1.LabelSelector `json:"namespaceSelector"`
+		EnableDryRunEndpoint        bool                  `json:"enableDryRunEndpoint"`
+		EnableFailurePolicyEndpoint bool                  `json:"enableFailurePolicyEndpoint"`
 	}
 
 	// ProfileValidator has all the profile validator's Helm variables
@@ -289,6 +297,212 @@ func (v Values) Merge(src Values) (Values, error) {
 	return src, nil
 }
 
+// Warnings returns the settings that are valid but likely unintended, such
+// as running in HA mode with a single controller replica. Unlike Validate's
+// errors, they don't prevent upgrading existing installations.
+func (v *Values) Warnings() []string {
+	var warnings []string
+
+	if v.HighAvailability && v.ControllerReplicas == 1 {
+		warnings = append(warnings, "controllerReplicas should be greater than 1 in HA mode, was 1")
+	}
+
+	return warnings
+}
+
+// Validate checks that the resource limits of every component aren't lower
+// than their requests, and that the replica settings are valid. All the
+// problems found are reported in the returned error.
+func (v *Values) Validate() error {
+	var errs []string
+
+	if v.ControllerReplicas == 0 {
+		errs = append(errs, "controllerReplicas must be at least 1")
+	}
+
+	if v.Identity != nil && v.Identity.Issuer != nil {
+		errs = append(errs, v.Identity.Issuer.validate()...)
+	}
+
+	if v.HeartbeatSchedule != "" {
+		if err := validateCronSchedule(v.HeartbeatSchedule); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid heartbeatSchedule '%s': %s", v.HeartbeatSchedule, err))
+		}
+	}
+
+	type namedResources struct {
+		name      string
+		resources *Resources
+	}
+	resources := []namedResources{
+		{"destinationResources", v.DestinationResources},
+		{"heartbeatResources", v.HeartbeatResources},
+		{"identityResources", v.IdentityResources},
+		{"proxyInjectorResources", v.ProxyInjectorResources},
+		{"destinationProxyResources", v.DestinationProxyResources},
+		{"identityProxyResources", v.IdentityProxyResources},
+		{"proxyInjectorProxyResources", v.ProxyInjectorProxyResources},
+	}
+	if v.Proxy != nil {
+		resources = append(resources, namedResources{"proxy.resources", v.Proxy.Resources})
+	}
+	if v.ProxyInit != nil {
+		resources = append(resources, namedResources{"proxyInit.resources", v.ProxyInit.Resources})
+	}
+	for _, r := range resources {
+		if r.resources == nil {
+			continue
+		}
+		errs = append(errs, r.resources.CPU.validate(r.name+".cpu")...)
+		errs = append(errs, r.resources.Memory.validate(r.name+".memory")...)
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// validate checks that the clock skew allowance and issuance lifetime are
+// valid durations, and that the former is shorter than the latter
+func (i *Issuer) validate() []string {
+	var errs []string
+	clockSkewAllowance, err := time.ParseDuration(i.ClockSkewAllowance)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("invalid identity.issuer.clockSkewAllowance '%s': %s", i.ClockSkewAllowance, err))
+	}
+	issuanceLifetime, lifetimeErr := time.ParseDuration(i.IssuanceLifetime)
+	if lifetimeErr != nil {
+		errs = append(errs, fmt.Sprintf("invalid identity.issuer.issuanceLifetime '%s': %s", i.IssuanceLifetime, lifetimeErr))
+	}
+	if err == nil && lifetimeErr == nil && clockSkewAllowance >= issuanceLifetime {
+		errs = append(errs, fmt.Sprintf("identity.issuer.clockSkewAllowance '%s' must be shorter than identity.issuer.issuanceLifetime '%s'", i.ClockSkewAllowance, i.IssuanceLifetime))
+	}
+	return errs
+}
+
+// validate checks that the limit and request are valid quantities and that
+// the limit isn't lower than the request
+func (c Constraints) validate(name string) []string {
+	var errs []string
+	var request, limit resource.Quantity
+	var err error
+	if c.Request != "" {
+		if request, err = resource.ParseQuantity(c.Request); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s.request '%s': %s", name, c.Request, err))
+		}
+	}
+	if c.Limit != "" {
+		if limit, err = resource.ParseQuantity(c.Limit); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s.limit '%s': %s", name, c.Limit, err))
+		}
+	}
+	if len(errs) == 0 && c.Request != "" && c.Limit != "" && limit.Cmp(request) < 0 {
+		errs = append(errs, fmt.Sprintf("%s.limit '%s' cannot be lower than %s.request '%s'", name, c.Limit, name, c.Request))
+	}
+	return errs
+}
+
+// cronField holds the bounds and value names of a field of a cron schedule
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+	anyValue bool
+}
+
+var (
+	cronFields = []cronField{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31, anyValue: true},
+		{name: "month", min: 1, max: 12, names: map[string]int{
+			"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+			"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+		}},
+		{name: "day of week", min: 0, max: 6, anyValue: true, names: map[string]int{
+			"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+		}},
+	}
+
+	cronDescriptors = map[string]struct{}{
+		"@yearly": {}, "@annually": {}, "@monthly": {}, "@weekly": {},
+		"@daily": {}, "@midnight": {}, "@hourly": {},
+	}
+)
+
+// validateCronSchedule checks that schedule is a standard five-field cron
+// expression, or one of the descriptors accepted by CronJobs (e.g. @daily,
+// @every 1h)
+func validateCronSchedule(schedule string) error {
+	if strings.HasPrefix(schedule, "@every ") {
+		_, err := time.ParseDuration(strings.TrimPrefix(schedule, "@every "))
+		return err
+	}
+	if strings.HasPrefix(schedule, "@") {
+		if _, ok := cronDescriptors[schedule]; !ok {
+			return fmt.Errorf("unrecognized descriptor %s", schedule)
+		}
+		return nil
+	}
+
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("expected %d fields, found %d", len(cronFields), len(fields))
+	}
+	for i, field := range fields {
+		if err := cronFields[i].validate(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks each of the comma-separated ranges of value, which can
+// have a step
+func (f cronField) validate(value string) error {
+	for _, expr := range strings.Split(value, ",") {
+		rangeExpr, step := expr, ""
+		if i := strings.Index(expr, "/"); i >= 0 {
+			rangeExpr, step = expr[:i], expr[i+1:]
+			if n, err := strconv.Atoi(step); err != nil || n < 1 {
+				return fmt.Errorf("invalid step '%s' in %s field", step, f.name)
+			}
+		}
+
+		if rangeExpr == "*" || (rangeExpr == "?" && f.anyValue) {
+			continue
+		}
+
+		bounds := strings.SplitN(rangeExpr, "-", 2)
+		start, err := f.parseValue(bounds[0])
+		if err != nil {
+			return err
+		}
+		if len(bounds) == 2 {
+			end, err := f.parseValue(bounds[1])
+			if err != nil {
+				return err
+			}
+			if start > end {
+				return fmt.Errorf("invalid range '%s' in %s field", rangeExpr, f.name)
+			}
+		}
+	}
+	return nil
+}
+
+func (f cronField) parseValue(value string) (int, error) {
+	if n, ok := f.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("invalid value '%s' in %s field, must be between %d and %d", value, f.name, f.min, f.max)
+	}
+	return n, nil
+}
+
 // ToMap converts the Values intro a map[string]interface{}
 func (v *Values) ToMap() (map[string]interface{}, error) {
 	var valuesMap map[string]interface{}
@@ -4,7 +4,9 @@ import (
 	"reflect"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 
 	"github.com/linkerd/linkerd2/pkg/version"
 )
@@ -142,6 +144,44 @@ func TestNewValues(t *testing.T) {
 		t.Errorf("Mismatch Helm values.\nExpected: %+v\nActual: %+v", expected, actual)
 	}
 
+	if len(actual.Proxy.ExtraEnv) != 0 {
+		t.Errorf("Expected no extra proxy environment variables by default, got %v", actual.Proxy.ExtraEnv)
+	}
+
+	if len(actual.Tolerations) != 0 {
+		t.Errorf("Expected no tolerations by default, got %v", actual.Tolerations)
+	}
+
+	if actual.PriorityClassName != "" {
+		t.Errorf("Expected no priority class by default, got %s", actual.PriorityClassName)
+	}
+
+	t.Run("Tolerations", func(t *testing.T) {
+		var values Values
+		err := yaml.Unmarshal([]byte(`
+tolerations:
+- key: dedicated
+  operator: Equal
+  value: linkerd
+  effect: NoSchedule
+`), &values)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+
+		expected := []corev1.Toleration{
+			{
+				Key:      "dedicated",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "linkerd",
+				Effect:   corev1.TaintEffectNoSchedule,
+			},
+		}
+		if !reflect.DeepEqual(expected, values.Tolerations) {
+			t.Errorf("Mismatch tolerations.\nExpected: %+v\nActual: %+v", expected, values.Tolerations)
+		}
+	})
+
 	t.Run("HA", func(t *testing.T) {
 		err := MergeHAValues(actual)
 
@@ -199,3 +239,120 @@ func TestNewValues(t *testing.T) {
 		}
 	})
 }
+
+func TestValidate(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		values, err := NewValues()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if err := values.Validate(); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+
+		if err := MergeHAValues(values); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		values.HighAvailability = true
+		if err := values.Validate(); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+	})
+
+	t.Run("HA with a single replica", func(t *testing.T) {
+		values, err := NewValues()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		values.HighAvailability = true
+		if err := values.Validate(); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+
+		expected := []string{"controllerReplicas should be greater than 1 in HA mode, was 1"}
+		if warnings := values.Warnings(); !reflect.DeepEqual(warnings, expected) {
+			t.Fatalf("Expected warnings %v, got %v", expected, warnings)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		values, err := NewValues()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		values.HighAvailability = true
+		values.DestinationResources = &Resources{
+			CPU:    Constraints{Limit: "100m", Request: "200m"},
+			Memory: Constraints{Limit: "250Mi", Request: "50Mi"},
+		}
+		values.Proxy.Resources.Memory = Constraints{Limit: "lots", Request: "20Mi"}
+
+		values.Identity.Issuer.ClockSkewAllowance = "20"
+		values.Identity.Issuer.IssuanceLifetime = "24h"
+
+		expected := `invalid identity.issuer.clockSkewAllowance '20': time: missing unit in duration "20"
+destinationResources.cpu.limit '100m' cannot be lower than destinationResources.cpu.request '200m'
+invalid proxy.resources.memory.limit 'lots': quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`
+		err = values.Validate()
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error:\n%s\nGot:\n%v", expected, err)
+		}
+	})
+
+	t.Run("heartbeat schedule", func(t *testing.T) {
+		testCases := []struct {
+			schedule string
+			expected string
+		}{
+			{"0 0 * * *", ""},
+			{"*/15 2-4,6 1,15 jan-jun MON-FRI", ""},
+			{"0 0 ? * 0", ""},
+			{"@daily", ""},
+			{"@every 6h", ""},
+			{"0 0 * *", "invalid heartbeatSchedule '0 0 * *': expected 5 fields, found 4"},
+			{"60 0 * * *", "invalid heartbeatSchedule '60 0 * * *': invalid value '60' in minute field, must be between 0 and 59"},
+			{"0 0 0 * *", "invalid heartbeatSchedule '0 0 0 * *': invalid value '0' in day of month field, must be between 1 and 31"},
+			{"0 5-2 * * *", "invalid heartbeatSchedule '0 5-2 * * *': invalid range '5-2' in hour field"},
+			{"*/0 * * * *", "invalid heartbeatSchedule '*/0 * * * *': invalid step '0' in minute field"},
+			{"? * * * *", "invalid heartbeatSchedule '? * * * *': invalid value '?' in minute field, must be between 0 and 59"},
+			{"@sometimes", "invalid heartbeatSchedule '@sometimes': unrecognized descriptor @sometimes"},
+		}
+
+		for _, tc := range testCases {
+			tc := tc // pin
+			t.Run(tc.schedule, func(t *testing.T) {
+				values, err := NewValues()
+				if err != nil {
+					t.Fatalf("Unexpected error: %v\n", err)
+				}
+				values.HeartbeatSchedule = tc.schedule
+
+				err = values.Validate()
+				if tc.expected == "" {
+					if err != nil {
+						t.Fatalf("Unexpected error: %v\n", err)
+					}
+					return
+				}
+				if err == nil || err.Error() != tc.expected {
+					t.Fatalf("Expected error:\n%s\nGot:\n%v", tc.expected, err)
+				}
+			})
+		}
+	})
+
+	t.Run("clock skew allowance longer than issuance lifetime", func(t *testing.T) {
+		values, err := NewValues()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		values.Identity.Issuer.ClockSkewAllowance = "2h"
+		values.Identity.Issuer.IssuanceLifetime = "1h"
+
+		expected := "identity.issuer.clockSkewAllowance '2h' must be shorter than identity.issuer.issuanceLifetime '1h'"
+		err = values.Validate()
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error:\n%s\nGot:\n%v", expected, err)
+		}
+	})
+}
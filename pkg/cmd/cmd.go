@@ -146,3 +146,12 @@ func GetLabelSelector(labelKey string, labelValues ...string) (string, error) {
 	selector := labels.NewSelector().Add(*labelRequirement)
 	return selector.String(), nil
 }
+
+// ValidateImpersonation checks that groups are only impersonated along with a
+// user, as Kubernetes otherwise rejects the requests with an obscure error.
+func ValidateImpersonation(impersonate string, impersonateGroup []string) error {
+	if impersonate == "" && len(impersonateGroup) > 0 {
+		return errors.New("--as-group requires --as to be set")
+	}
+	return nil
+}
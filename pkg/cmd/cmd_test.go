@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestValidateImpersonation(t *testing.T) {
+	testCases := []struct {
+		impersonate      string
+		impersonateGroup []string
+		expectedError    string
+	}{
+		{"", nil, ""},
+		{"alice", nil, ""},
+		{"alice", []string{"admins"}, ""},
+		{"", []string{"admins"}, "--as-group requires --as to be set"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.impersonate, func(t *testing.T) {
+			err := ValidateImpersonation(tc.impersonate, tc.impersonateGroup)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedError {
+				t.Fatalf("Expected error [%s], got [%v]", tc.expectedError, err)
+			}
+		})
+	}
+}
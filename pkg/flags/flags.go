@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"runtime"
 
 	"github.com/linkerd/linkerd2/pkg/version"
 	log "github.com/sirupsen/logrus"
@@ -65,15 +66,22 @@ func maybePrintVersionAndExit(printVersion bool) {
 		fmt.Println(version.Version)
 		os.Exit(0)
 	}
-	log.Infof("running version %s", version.Version)
+	if version.GitSHA != "" {
+		log.Infof("running version %s (git SHA %s, %s)", version.Version, version.GitSHA, runtime.Version())
+	} else {
+		log.Infof("running version %s (%s)", version.Version, runtime.Version())
+	}
 }
 
 func getFormatter(format string) log.Formatter {
 	switch format {
 	case "json":
 		return &log.JSONFormatter{}
-	default:
+	case "plain":
 		return &log.TextFormatter{FullTimestamp: true}
+	default:
+		log.Fatalf("invalid log-format: %s", format)
+		return nil
 	}
 }
 
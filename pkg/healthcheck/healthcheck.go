@@ -25,6 +25,7 @@ import (
 	admissionRegistration "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -151,11 +152,14 @@ const (
 	LinkerdCNIResourceLabel = "linkerd.io/cni-resource"
 
 	linkerdCNIDisabledSkipReason = "skipping check because CNI is not enabled"
+	versionChecksSkipReason      = "skipping check because version checks are disabled"
 	linkerdCNIResourceName       = "linkerd-cni"
 	linkerdCNIConfigMapName      = "linkerd-cni-config"
 
 	podCIDRUnavailableSkipReason = "skipping check because the nodes aren't exposing podCIDR"
 
+	destinationSelfCheckUnavailableSkipReason = "skipping check because the destination controller doesn't serve the self-check"
+
 	proxyInjectorOldTLSSecretName = "linkerd-proxy-injector-tls"
 	proxyInjectorTLSSecretName    = "linkerd-proxy-injector-k8s-tls"
 	spValidatorOldTLSSecretName   = "linkerd-sp-validator-tls"
@@ -180,6 +184,13 @@ var linkerdHAControlPlaneComponents = []string{
 	"linkerd-proxy-injector",
 }
 
+// ExpectedCRDVersions maps the CRDs the control plane watches to the version
+// it watches them at
+var ExpectedCRDVersions = map[string]string{
+	"serviceprofiles.linkerd.io":      "v1alpha2",
+	"trafficsplits.split.smi-spec.io": "v1alpha1",
+}
+
 // ExpectedServiceAccountNames is a list of the service accounts that a healthy
 // Linkerd installation should have. Note that linkerd-heartbeat is optional,
 // so it doesn't appear here.
@@ -191,6 +202,8 @@ var ExpectedServiceAccountNames = []string{
 
 var (
 	retryWindow = 5 * time.Second
+	// maxRetryWindow caps the backoff between retries of a failing check
+	maxRetryWindow = 20 * time.Second
 	// RequestTimeout is the time it takes for a request to timeout
 	RequestTimeout = 30 * time.Second
 )
@@ -397,8 +410,12 @@ type Options struct {
 	Impersonate           string
 	ImpersonateGroup      []string
 	APIAddr               string
+	APITimeout            time.Duration
+	TraceAPIRequests      bool
 	VersionOverride       string
+	SkipVersionChecks     bool
 	RetryDeadline         time.Time
+	RetryWindow           time.Duration
 	CNIEnabled            bool
 	InstallManifest       string
 }
@@ -439,6 +456,11 @@ func NewHealthChecker(categoryIDs []CategoryID, options *Options) *HealthChecker
 	for _, category := range categoryIDs {
 		checkMap[category] = struct{}{}
 	}
+	// the latest versions can't be determined without reaching out to the
+	// version check endpoint, which may not be reachable
+	if options.SkipVersionChecks {
+		delete(checkMap, LinkerdVersionChecks)
+	}
 	for i := range hc.categories {
 		if _, ok := checkMap[hc.categories[i].ID]; ok {
 			hc.categories[i].enabled = true
@@ -592,6 +614,20 @@ func (hc *HealthChecker) allCategories() []*Category {
 						return hc.checkCanCreate(ctx, hc.ControlPlaneNamespace, "batch", "v1beta1", "cronjobs")
 					},
 				},
+				{
+					description: "can create Roles",
+					hintAnchor:  "pre-k8s",
+					check: func(ctx context.Context) error {
+						return hc.checkCanCreate(ctx, hc.ControlPlaneNamespace, "rbac.authorization.k8s.io", "v1", "roles")
+					},
+				},
+				{
+					description: "can create RoleBindings",
+					hintAnchor:  "pre-k8s",
+					check: func(ctx context.Context) error {
+						return hc.checkCanCreate(ctx, hc.ControlPlaneNamespace, "rbac.authorization.k8s.io", "v1", "rolebindings")
+					},
+				},
 				{
 					description: "can create ConfigMaps",
 					hintAnchor:  "pre-k8s",
@@ -713,6 +749,13 @@ func (hc *HealthChecker) allCategories() []*Category {
 						return
 					},
 				},
+				{
+					description: "'linkerd-config' config map is valid",
+					hintAnchor:  "l5d-existence-linkerd-config",
+					check: func(ctx context.Context) error {
+						return validateLinkerdConfig(hc.linkerdConfig)
+					},
+				},
 				{
 					description: "heartbeat ServiceAccount exist",
 					hintAnchor:  "l5d-existence-sa",
@@ -768,6 +811,15 @@ func (hc *HealthChecker) allCategories() []*Category {
 						return validateControlPlanePods(hc.controlPlanePods)
 					},
 				},
+				{
+					description:         "destination informers are synced",
+					hintAnchor:          "l5d-destination-informers-synced",
+					retryDeadline:       hc.RetryDeadline,
+					surfaceErrorOnRetry: true,
+					check: func(ctx context.Context) error {
+						return hc.checkDestinationSelfCheck(ctx)
+					},
+				},
 				{
 					description: "cluster networks contains all node podCIDRs",
 					hintAnchor:  "l5d-cluster-networks-cidr",
@@ -827,6 +879,13 @@ func (hc *HealthChecker) allCategories() []*Category {
 						return hc.checkCustomResourceDefinitions(ctx, true)
 					},
 				},
+				{
+					description: "control plane CustomResourceDefinitions serve the expected versions",
+					hintAnchor:  "l5d-existence-crd",
+					check: func(ctx context.Context) error {
+						return hc.checkCustomResourceDefinitionVersions(ctx)
+					},
+				},
 				{
 					description: "control plane MutatingWebhookConfigurations exist",
 					hintAnchor:  "l5d-existence-mwc",
@@ -1249,6 +1308,9 @@ func (hc *HealthChecker) allCategories() []*Category {
 					hintAnchor:  "l5d-version-control",
 					warning:     true,
 					check: func(context.Context) error {
+						if hc.SkipVersionChecks {
+							return &SkipError{Reason: versionChecksSkipReason}
+						}
 						return hc.LatestVersions.Match(hc.serverVersion)
 					},
 				},
@@ -1402,6 +1464,14 @@ func (hc *HealthChecker) allCategories() []*Category {
 						return checkMisconfiguredServiceAnnotations(services)
 					},
 				},
+				{
+					description: "services selecting meshed pods have endpoints",
+					hintAnchor:  "l5d-data-plane-endpoints",
+					warning:     true,
+					check: func(ctx context.Context) error {
+						return hc.checkDataPlaneEndpoints(ctx)
+					},
+				},
 				{
 					description: "opaque ports are properly annotated",
 					hintAnchor:  "linkerd-opaque-ports-definition",
@@ -1451,6 +1521,9 @@ func (hc *HealthChecker) allCategories() []*Category {
 // CheckProxyVersionsUpToDate checks if all the proxies are on the latest
 // installed version
 func (hc *HealthChecker) CheckProxyVersionsUpToDate(pods []corev1.Pod) error {
+	if hc.SkipVersionChecks {
+		return &SkipError{Reason: versionChecksSkipReason}
+	}
 	return CheckProxyVersionsUpToDate(pods, hc.LatestVersions)
 }
 
@@ -1629,13 +1702,31 @@ func (hc *HealthChecker) RunChecks(observer CheckObserver) bool {
 	return success
 }
 
+// RunChecksCollect runs all configured checkers like RunChecks, and returns
+// the result of each of them along with the overall success. Intermediate
+// results of checks that are being retried aren't included.
+func (hc *HealthChecker) RunChecksCollect() ([]CheckResult, bool) {
+	var results []CheckResult
+	success := hc.RunChecks(func(result *CheckResult) {
+		if !result.Retry {
+			results = append(results, *result)
+		}
+	})
+	return results, success
+}
+
 // LinkerdConfig gets the Linkerd configuration values.
 func (hc *HealthChecker) LinkerdConfig() *l5dcharts.Values {
 	return hc.linkerdConfig
 }
 
 func (hc *HealthChecker) runCheck(category *Category, c *Checker, observer CheckObserver) bool {
-	for {
+	wait := hc.RetryWindow
+	if wait == 0 {
+		wait = retryWindow
+	}
+
+	for attempt := 1; ; attempt++ {
 		ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
 		defer cancel()
 		err := c.check(ctx)
@@ -1661,10 +1752,14 @@ func (hc *HealthChecker) runCheck(category *Category, c *Checker, observer Check
 			if !c.surfaceErrorOnRetry {
 				checkResult.Err = errors.New("waiting for check to complete")
 			}
-			log.Debugf("Retrying on error: %s", err)
+			if remaining := time.Until(c.retryDeadline); wait > remaining {
+				wait = remaining
+			}
+			log.Debugf("Retrying in %s after attempt %d failed: %s", wait, attempt, err)
 
 			observer(checkResult)
-			time.Sleep(retryWindow)
+			time.Sleep(wait)
+			wait = nextRetryWindow(wait)
 			continue
 		}
 
@@ -1673,6 +1768,18 @@ func (hc *HealthChecker) runCheck(category *Category, c *Checker, observer Check
 	}
 }
 
+// nextRetryWindow doubles the time to wait before retrying a check, up to
+// maxRetryWindow, unless the current window was set higher than that
+func nextRetryWindow(window time.Duration) time.Duration {
+	if window >= maxRetryWindow {
+		return window
+	}
+	if window*2 > maxRetryWindow {
+		return maxRetryWindow
+	}
+	return window * 2
+}
+
 func (hc *HealthChecker) controlPlaneComponentsSelector() string {
 	return fmt.Sprintf("%s,!%s", k8s.ControllerNSLabel, LinkerdCNIResourceLabel)
 }
@@ -1697,6 +1804,32 @@ func (hc *HealthChecker) checkLinkerdConfigConfigMap(ctx context.Context) (strin
 	return string(configMap.GetUID()), values, nil
 }
 
+// validateLinkerdConfig checks that the fields the control plane components
+// need from the linkerd-config values are present and parseable
+func validateLinkerdConfig(values *l5dcharts.Values) error {
+	if values == nil {
+		return errors.New("'linkerd-config' config map doesn't hold any configuration values")
+	}
+
+	var errs []string
+	if values.ClusterDomain == "" {
+		errs = append(errs, "clusterDomain is empty")
+	}
+	if values.IdentityTrustDomain == "" {
+		errs = append(errs, "identityTrustDomain is empty")
+	}
+	if values.IdentityTrustAnchorsPEM == "" {
+		errs = append(errs, "identityTrustAnchorsPEM is empty")
+	} else if _, err := tls.DecodePEMCertificates(values.IdentityTrustAnchorsPEM); err != nil {
+		errs = append(errs, fmt.Sprintf("identityTrustAnchorsPEM is invalid: %s", err))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("Invalid 'linkerd-config' config map:\n\t* %s", strings.Join(errs, "\n\t* "))
+	}
+	return nil
+}
+
 // Checks whether the configuration of the linkerd-identity-issuer is correct. This means:
 // 1. There is a config map present with identity context
 // 2. The scheme in the identity context corresponds to the format of the issuer secret
@@ -2079,7 +2212,49 @@ func (hc *HealthChecker) checkCustomResourceDefinitions(ctx context.Context, sho
 		objects = append(objects, &item)
 	}
 
-	return checkResources("CustomResourceDefinitions", objects, []string{"serviceprofiles.linkerd.io"}, shouldExist)
+	return checkResources("CustomResourceDefinitions", objects, []string{"serviceprofiles.linkerd.io", "trafficsplits.split.smi-spec.io"}, shouldExist)
+}
+
+func (hc *HealthChecker) checkCustomResourceDefinitionVersions(ctx context.Context) error {
+	crds := []apiextv1.CustomResourceDefinition{}
+	for name := range ExpectedCRDVersions {
+		crd, err := hc.kubeAPI.Apiextensions.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		crds = append(crds, *crd)
+	}
+
+	return checkCRDVersions(crds)
+}
+
+// checkCRDVersions returns an error listing the CRDs not serving the version
+// the control plane watches them at
+func checkCRDVersions(crds []apiextv1.CustomResourceDefinition) error {
+	var outdated []string
+	for _, crd := range crds {
+		expected, ok := ExpectedCRDVersions[crd.Name]
+		if !ok {
+			continue
+		}
+
+		served := false
+		for _, v := range crd.Spec.Versions {
+			if v.Name == expected && v.Served {
+				served = true
+				break
+			}
+		}
+		if !served {
+			outdated = append(outdated, fmt.Sprintf("\t* %s doesn't serve version %s", crd.Name, expected))
+		}
+	}
+
+	if len(outdated) > 0 {
+		sort.Strings(outdated)
+		return fmt.Errorf("Some CustomResourceDefinitions are outdated; re-run 'linkerd upgrade' (or install) to update them:\n%s", strings.Join(outdated, "\n"))
+	}
+	return nil
 }
 
 func (hc *HealthChecker) getProxyInjectorMutatingWebhook(ctx context.Context) (*admissionRegistration.MutatingWebhook, error) {
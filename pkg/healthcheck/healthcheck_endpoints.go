@@ -0,0 +1,185 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	destinationPb "github.com/linkerd/linkerd2-proxy-api/go/destination"
+	"github.com/linkerd/linkerd2/controller/api/destination"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// maxEndpointsCheckServices bounds the number of services resolved through
+	// the destination API, to keep the check fast on large meshes
+	maxEndpointsCheckServices = 20
+
+	endpointsResolveTimeout = 5 * time.Second
+	defaultClusterDomain    = "cluster.local"
+)
+
+// serviceWithPod is a service selecting at least one meshed pod, along with
+// the first such pod
+type serviceWithPod struct {
+	service corev1.Service
+	pod     string
+}
+
+func (hc *HealthChecker) checkDataPlaneEndpoints(ctx context.Context) error {
+	pods, err := hc.GetDataPlanePods(ctx)
+	if err != nil {
+		return err
+	}
+
+	services, err := hc.GetServices(ctx)
+	if err != nil {
+		return err
+	}
+
+	sampled := servicesSelectingPods(services, pods, maxEndpointsCheckServices)
+	if len(sampled) == 0 {
+		return nil
+	}
+
+	client, conn, err := destination.NewExternalClient(ctx, hc.ControlPlaneNamespace, hc.kubeAPI)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the destination API: %s", err)
+	}
+	defer conn.Close()
+
+	clusterDomain := defaultClusterDomain
+	if hc.linkerdConfig != nil && hc.linkerdConfig.ClusterDomain != "" {
+		clusterDomain = hc.linkerdConfig.ClusterDomain
+	}
+
+	return checkServicesHaveEndpoints(ctx, client, sampled, clusterDomain)
+}
+
+// checkDestinationSelfCheck fetches the self-check of the destination
+// controller, and returns an error if its informers aren't synced. The check is
+// skipped for destination controllers that don't serve the self-check.
+func (hc *HealthChecker) checkDestinationSelfCheck(ctx context.Context) error {
+	return validateDestinationSelfCheck(destination.GetExternalSelfCheck(ctx, hc.ControlPlaneNamespace, hc.kubeAPI))
+}
+
+func validateDestinationSelfCheck(result *destination.SelfCheckResult, err error) error {
+	if errors.Is(err, destination.ErrSelfCheckNotFound) {
+		return &SkipError{Reason: destinationSelfCheckUnavailableSkipReason}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch the destination self-check: %s", err)
+	}
+	if !result.InformersSynced {
+		return fmt.Errorf("The destination informers aren't synced yet (watching %d services)", result.WatchedServices)
+	}
+	return nil
+}
+
+// servicesSelectingPods returns up to max services, sorted by namespace and
+// name, whose selector matches at least one of the given pods
+func servicesSelectingPods(services []corev1.Service, pods []corev1.Pod, max int) []serviceWithPod {
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].Namespace != services[j].Namespace {
+			return services[i].Namespace < services[j].Namespace
+		}
+		return services[i].Name < services[j].Name
+	})
+
+	var selecting []serviceWithPod
+	for _, svc := range services {
+		if len(selecting) == max {
+			break
+		}
+		if len(svc.Spec.Selector) == 0 || len(svc.Spec.Ports) == 0 {
+			continue
+		}
+
+		selector := labels.SelectorFromSet(svc.Spec.Selector)
+		for _, pod := range pods {
+			if pod.Namespace == svc.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+				selecting = append(selecting, serviceWithPod{svc, pod.Name})
+				break
+			}
+		}
+	}
+
+	return selecting
+}
+
+// checkServicesHaveEndpoints resolves all the services at the same time
+// through the destination API, so that the check takes at most
+// endpointsResolveTimeout, and returns an error listing the ones without any
+// ready endpoint and the ones that couldn't be resolved
+func checkServicesHaveEndpoints(ctx context.Context, client destinationPb.DestinationClient, services []serviceWithPod, clusterDomain string) error {
+	type result struct {
+		ready bool
+		err   error
+	}
+	results := make([]result, len(services))
+
+	var wg sync.WaitGroup
+	for i, s := range services {
+		wg.Add(1)
+		go func(i int, s serviceWithPod) {
+			defer wg.Done()
+			authority := fmt.Sprintf("%s.%s.svc.%s:%d", s.service.Name, s.service.Namespace, clusterDomain, s.service.Spec.Ports[0].Port)
+			ready, err := hasEndpoints(ctx, client, authority)
+			results[i] = result{ready, err}
+		}(i, s)
+	}
+	wg.Wait()
+
+	var noEndpoints, failed []string
+	for i, s := range services {
+		switch {
+		case results[i].err != nil:
+			failed = append(failed,
+				fmt.Sprintf("\t* %s/%s: %s", s.service.Namespace, s.service.Name, results[i].err))
+		case !results[i].ready:
+			noEndpoints = append(noEndpoints,
+				fmt.Sprintf("\t* %s/%s (selecting pod %s)", s.service.Namespace, s.service.Name, s.pod))
+		}
+	}
+
+	var errs []string
+	if len(noEndpoints) > 0 {
+		errs = append(errs, fmt.Sprintf("Some services selecting meshed pods have no ready endpoints:\n%s", strings.Join(noEndpoints, "\n")))
+	}
+	if len(failed) > 0 {
+		errs = append(errs, fmt.Sprintf("Some services couldn't be resolved:\n%s", strings.Join(failed, "\n")))
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func hasEndpoints(ctx context.Context, client destinationPb.DestinationClient, authority string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, endpointsResolveTimeout)
+	defer cancel()
+
+	rsp, err := client.Get(ctx, &destinationPb.GetDestination{
+		Scheme: "http:",
+		Path:   authority,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	update, err := rsp.Recv()
+	if err != nil {
+		return false, err
+	}
+
+	// the first update is either the current set of addresses, or NoEndpoints
+	if add, ok := update.GetUpdate().(*destinationPb.Update_Add); ok {
+		return len(add.Add.GetAddrs()) > 0, nil
+	}
+	return false, nil
+}
@@ -61,6 +61,31 @@ func (cr CheckResults) RunChecks(observer CheckObserver) bool {
 	return success
 }
 
+// failOnWarningRunner wraps a Runner, reporting checks that result in a
+// warning as failures.
+type failOnWarningRunner struct {
+	Runner
+}
+
+// FailOnWarning returns a Runner that behaves like r, except that RunChecks
+// returns false if any check results in a warning.
+func FailOnWarning(r Runner) Runner {
+	return failOnWarningRunner{r}
+}
+
+// RunChecks runs the wrapped Runner's checks, additionally flagging warnings
+// as failures.
+func (r failOnWarningRunner) RunChecks(observer CheckObserver) bool {
+	warned := false
+	success := r.Runner.RunChecks(func(result *CheckResult) {
+		if !result.Retry && result.Err != nil && result.Warning {
+			warned = true
+		}
+		observer(result)
+	})
+	return success && !warned
+}
+
 // PrintCoreChecksHeader writes the core checks header.
 func PrintCoreChecksHeader(wout io.Writer) {
 	headerTxt := "Linkerd core checks"
@@ -71,8 +96,9 @@ func PrintCoreChecksHeader(wout io.Writer) {
 
 // RunExtensionsChecks runs checks for each extension name passed into the `extensions` parameter
 // and handles formatting the output for each extension's check. This function also handles
-// finding the extension in the user's path and runs it.
-func RunExtensionsChecks(wout io.Writer, werr io.Writer, extensions []string, flags []string, output string) bool {
+// finding the extension in the user's path and runs it. If failOnWarning is
+// set, extension checks resulting in a warning are considered failures.
+func RunExtensionsChecks(wout io.Writer, werr io.Writer, extensions []string, flags []string, output string, failOnWarning bool) bool {
 	if output != JSONOutput {
 		headerTxt := "Linkerd extensions checks"
 		fmt.Fprintln(wout)
@@ -148,7 +174,11 @@ func RunExtensionsChecks(wout io.Writer, werr io.Writer, extensions []string, fl
 		}
 		// add a new line to space out each check output
 		fmt.Fprintln(wout)
-		extensionSuccess := RunChecks(wout, werr, results, fmt.Sprintf("extension-%s", output))
+		var runner Runner = results
+		if failOnWarning {
+			runner = FailOnWarning(results)
+		}
+		extensionSuccess := RunChecks(wout, werr, runner, fmt.Sprintf("extension-%s", output))
 		if !extensionSuccess {
 			success = false
 		}
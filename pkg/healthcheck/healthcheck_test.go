@@ -6,12 +6,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/golang/protobuf/ptypes/duration"
+	destinationPb "github.com/linkerd/linkerd2-proxy-api/go/destination"
+	"github.com/linkerd/linkerd2/controller/api/destination"
 	configPb "github.com/linkerd/linkerd2/controller/gen/config"
 	"github.com/linkerd/linkerd2/pkg/charts/linkerd2"
 	"github.com/linkerd/linkerd2/pkg/identity"
@@ -19,8 +24,10 @@ import (
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/tls"
 	"github.com/linkerd/linkerd2/testutil"
+	"google.golang.org/grpc"
 	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -350,6 +357,182 @@ func TestHealthChecker(t *testing.T) {
 			t.Fatalf("Expected results %v, but got %v", expectedResults, obs.results)
 		}
 	})
+
+	t.Run("Collects all results", func(t *testing.T) {
+		hc := NewHealthChecker(
+			[]CategoryID{},
+			&Options{},
+		)
+
+		hc.AppendCategories(passingCheck1)
+		hc.AppendCategories(skippingCheck)
+		hc.AppendCategories(failingCheck)
+		hc.AppendCategories(passingCheck2)
+
+		results, success := hc.RunChecksCollect()
+		if success {
+			t.Fatal("Expecting checks to fail, but got success")
+		}
+
+		expectedResults := []string{
+			"cat1 desc1",
+			"cat3 desc3: error",
+			"cat2 desc2",
+		}
+		actualResults := []string{}
+		for _, result := range results {
+			res := fmt.Sprintf("%s %s", result.Category, result.Description)
+			if result.Err != nil {
+				res += fmt.Sprintf(": %s", result.Err)
+			}
+			actualResults = append(actualResults, res)
+		}
+
+		if !reflect.DeepEqual(actualResults, expectedResults) {
+			t.Fatalf("Expected results %v, but got %v", expectedResults, actualResults)
+		}
+	})
+}
+
+func TestNextRetryWindow(t *testing.T) {
+	testCases := []struct {
+		window   time.Duration
+		expected time.Duration
+	}{
+		{0, 0},
+		{time.Second, 2 * time.Second},
+		{5 * time.Second, 10 * time.Second},
+		{15 * time.Second, maxRetryWindow},
+		{maxRetryWindow, maxRetryWindow},
+		{time.Minute, time.Minute},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.window.String(), func(t *testing.T) {
+			if next := nextRetryWindow(tc.window); next != tc.expected {
+				t.Fatalf("Expected next retry window %s, got %s", tc.expected, next)
+			}
+		})
+	}
+}
+
+func TestSkipVersionChecks(t *testing.T) {
+	hc := NewHealthChecker(
+		[]CategoryID{LinkerdVersionChecks, LinkerdControlPlaneVersionChecks},
+		&Options{SkipVersionChecks: true},
+	)
+
+	for _, category := range hc.categories {
+		switch category.ID {
+		case LinkerdVersionChecks:
+			if category.enabled {
+				t.Fatalf("Expected %s checks to be disabled", category.ID)
+			}
+		case LinkerdControlPlaneVersionChecks:
+			if !category.enabled {
+				t.Fatalf("Expected %s checks to be enabled", category.ID)
+			}
+		}
+	}
+
+	var skipErr *SkipError
+	if err := hc.CheckProxyVersionsUpToDate(nil); !errors.As(err, &skipErr) {
+		t.Fatalf("Expected a SkipError, got %v", err)
+	}
+}
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: production
+  cluster:
+    server: https://production.example.com
+- name: staging
+  cluster:
+    server: https://staging.example.com
+contexts:
+- name: production
+  context:
+    cluster: production
+    user: admin
+- name: staging
+  context:
+    cluster: staging
+    user: admin
+current-context: production
+users:
+- name: admin
+  user:
+    token: token
+`
+
+func writeTestKubeconfig(t *testing.T) string {
+	f, err := ioutil.TempFile("", "kubeconfig")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(testKubeconfig); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return f.Name()
+}
+
+func TestInitializeKubeAPIClient(t *testing.T) {
+	kubeconfig := writeTestKubeconfig(t)
+	defer os.Remove(kubeconfig)
+
+	hc := NewHealthChecker([]CategoryID{}, &Options{
+		KubeConfig:       kubeconfig,
+		Impersonate:      "jane",
+		ImpersonateGroup: []string{"ops"},
+	})
+	if err := hc.InitializeKubeAPIClient(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	impersonate := hc.kubeAPI.Config.Impersonate
+	if impersonate.UserName != "jane" || !reflect.DeepEqual(impersonate.Groups, []string{"ops"}) {
+		t.Fatalf("Expected to impersonate user jane in group ops, got %+v", impersonate)
+	}
+}
+
+func TestInitializeKubeAPIClientContext(t *testing.T) {
+	kubeconfig := writeTestKubeconfig(t)
+	defer os.Remove(kubeconfig)
+
+	testCases := []struct {
+		kubeContext  string
+		expectedHost string
+	}{
+		{"", "https://production.example.com"},
+		{"staging", "https://staging.example.com"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.kubeContext, func(t *testing.T) {
+			hc := NewHealthChecker([]CategoryID{}, &Options{
+				KubeConfig:  kubeconfig,
+				KubeContext: tc.kubeContext,
+			})
+			if err := hc.InitializeKubeAPIClient(); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if hc.kubeAPI.Config.Host != tc.expectedHost {
+				t.Fatalf("Expected host %s, got %s", tc.expectedHost, hc.kubeAPI.Config.Host)
+			}
+		})
+	}
+
+	hc := NewHealthChecker([]CategoryID{}, &Options{
+		KubeConfig:  kubeconfig,
+		KubeContext: "missing",
+	})
+	if err := hc.InitializeKubeAPIClient(); err == nil {
+		t.Fatal("Expected an error for a missing context, got nothing")
+	}
 }
 
 func TestCheckCanCreate(t *testing.T) {
@@ -660,7 +843,7 @@ metadata:
 				"linkerd-config control plane ClusterRoles exist",
 				"linkerd-config control plane ClusterRoleBindings exist",
 				"linkerd-config control plane ServiceAccounts exist",
-				"linkerd-config control plane CustomResourceDefinitions exist: missing CustomResourceDefinitions: serviceprofiles.linkerd.io",
+				"linkerd-config control plane CustomResourceDefinitions exist: missing CustomResourceDefinitions: serviceprofiles.linkerd.io, trafficsplits.split.smi-spec.io",
 			},
 		},
 		{
@@ -754,6 +937,22 @@ metadata:
   name: serviceprofiles.linkerd.io
   labels:
     linkerd.io/control-plane-ns: test-ns
+spec:
+  versions:
+  - name: v1alpha2
+    served: true
+`,
+				`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: trafficsplits.split.smi-spec.io
+  labels:
+    linkerd.io/control-plane-ns: test-ns
+spec:
+  versions:
+  - name: v1alpha1
+    served: true
 `,
 			},
 			[]string{
@@ -762,6 +961,7 @@ metadata:
 				"linkerd-config control plane ClusterRoleBindings exist",
 				"linkerd-config control plane ServiceAccounts exist",
 				"linkerd-config control plane CustomResourceDefinitions exist",
+				"linkerd-config control plane CustomResourceDefinitions serve the expected versions",
 				"linkerd-config control plane MutatingWebhookConfigurations exist: missing MutatingWebhookConfigurations: linkerd-proxy-injector-webhook-config",
 			},
 		},
@@ -856,6 +1056,22 @@ metadata:
   name: serviceprofiles.linkerd.io
   labels:
     linkerd.io/control-plane-ns: test-ns
+spec:
+  versions:
+  - name: v1alpha2
+    served: true
+`,
+				`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: trafficsplits.split.smi-spec.io
+  labels:
+    linkerd.io/control-plane-ns: test-ns
+spec:
+  versions:
+  - name: v1alpha1
+    served: true
 `,
 				`
 apiVersion: admissionregistration.k8s.io/v1
@@ -872,6 +1088,7 @@ metadata:
 				"linkerd-config control plane ClusterRoleBindings exist",
 				"linkerd-config control plane ServiceAccounts exist",
 				"linkerd-config control plane CustomResourceDefinitions exist",
+				"linkerd-config control plane CustomResourceDefinitions serve the expected versions",
 				"linkerd-config control plane MutatingWebhookConfigurations exist",
 				"linkerd-config control plane ValidatingWebhookConfigurations exist: missing ValidatingWebhookConfigurations: linkerd-sp-validator-webhook-config",
 			},
@@ -967,6 +1184,22 @@ metadata:
   name: serviceprofiles.linkerd.io
   labels:
     linkerd.io/control-plane-ns: test-ns
+spec:
+  versions:
+  - name: v1alpha2
+    served: true
+`,
+				`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: trafficsplits.split.smi-spec.io
+  labels:
+    linkerd.io/control-plane-ns: test-ns
+spec:
+  versions:
+  - name: v1alpha1
+    served: true
 `,
 				`
 apiVersion: admissionregistration.k8s.io/v1
@@ -991,6 +1224,7 @@ metadata:
 				"linkerd-config control plane ClusterRoleBindings exist",
 				"linkerd-config control plane ServiceAccounts exist",
 				"linkerd-config control plane CustomResourceDefinitions exist",
+				"linkerd-config control plane CustomResourceDefinitions serve the expected versions",
 				"linkerd-config control plane MutatingWebhookConfigurations exist",
 				"linkerd-config control plane ValidatingWebhookConfigurations exist",
 				"linkerd-config control plane PodSecurityPolicies exist: missing PodSecurityPolicies: linkerd-test-ns-control-plane",
@@ -1087,6 +1321,22 @@ metadata:
   name: serviceprofiles.linkerd.io
   labels:
     linkerd.io/control-plane-ns: test-ns
+spec:
+  versions:
+  - name: v1alpha2
+    served: true
+`,
+				`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: trafficsplits.split.smi-spec.io
+  labels:
+    linkerd.io/control-plane-ns: test-ns
+spec:
+  versions:
+  - name: v1alpha1
+    served: true
 `,
 				`
 apiVersion: admissionregistration.k8s.io/v1
@@ -1119,6 +1369,7 @@ metadata:
 				"linkerd-config control plane ClusterRoleBindings exist",
 				"linkerd-config control plane ServiceAccounts exist",
 				"linkerd-config control plane CustomResourceDefinitions exist",
+				"linkerd-config control plane CustomResourceDefinitions serve the expected versions",
 				"linkerd-config control plane MutatingWebhookConfigurations exist",
 				"linkerd-config control plane ValidatingWebhookConfigurations exist",
 				"linkerd-config control plane PodSecurityPolicies exist",
@@ -1151,6 +1402,98 @@ metadata:
 	}
 }
 
+func TestCheckCRDVersions(t *testing.T) {
+	crd := func(name string, versions ...apiextv1.CustomResourceDefinitionVersion) apiextv1.CustomResourceDefinition {
+		return apiextv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       apiextv1.CustomResourceDefinitionSpec{Versions: versions},
+		}
+	}
+
+	t.Run("Returns nil if the expected versions are served", func(t *testing.T) {
+		err := checkCRDVersions([]apiextv1.CustomResourceDefinition{
+			crd("serviceprofiles.linkerd.io",
+				apiextv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true},
+				apiextv1.CustomResourceDefinitionVersion{Name: "v1alpha2", Served: true}),
+			crd("trafficsplits.split.smi-spec.io",
+				apiextv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true}),
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	})
+
+	t.Run("Returns error if an expected version isn't served", func(t *testing.T) {
+		err := checkCRDVersions([]apiextv1.CustomResourceDefinition{
+			crd("serviceprofiles.linkerd.io",
+				apiextv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: true}),
+			crd("trafficsplits.split.smi-spec.io",
+				apiextv1.CustomResourceDefinitionVersion{Name: "v1alpha1", Served: false}),
+		})
+		expectedErrorMsg := "Some CustomResourceDefinitions are outdated; re-run 'linkerd upgrade' (or install) to update them:\n\t* serviceprofiles.linkerd.io doesn't serve version v1alpha2\n\t* trafficsplits.split.smi-spec.io doesn't serve version v1alpha1"
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != expectedErrorMsg {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+func TestValidateLinkerdConfig(t *testing.T) {
+	ca, err := tls.GenerateRootCAWithDefaults("identity.linkerd.cluster.local")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		description      string
+		values           *linkerd2.Values
+		expectedErrorMsg string
+	}{
+		{
+			"valid config",
+			&linkerd2.Values{
+				ClusterDomain:           "cluster.local",
+				IdentityTrustDomain:     "cluster.local",
+				IdentityTrustAnchorsPEM: ca.Cred.Crt.EncodePEM(),
+			},
+			"",
+		},
+		{
+			"no values",
+			nil,
+			"'linkerd-config' config map doesn't hold any configuration values",
+		},
+		{
+			"missing and malformed fields",
+			&linkerd2.Values{
+				IdentityTrustAnchorsPEM: "not a certificate",
+			},
+			"Invalid 'linkerd-config' config map:\n\t* clusterDomain is empty\n\t* identityTrustDomain is empty\n\t* identityTrustAnchorsPEM is invalid: ",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.description, func(t *testing.T) {
+			err := validateLinkerdConfig(tc.values)
+			if tc.expectedErrorMsg == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Expected error, got nothing")
+			}
+			if !strings.HasPrefix(err.Error(), tc.expectedErrorMsg) {
+				t.Fatalf("Unexpected error message: %s", err.Error())
+			}
+		})
+	}
+}
+
 func TestCheckControlPlanePodExistence(t *testing.T) {
 	var testCases = []struct {
 		checkDescription string
@@ -2009,6 +2352,188 @@ func TestServicesAnnotations(t *testing.T) {
 	})
 }
 
+func TestServicesHaveEndpoints(t *testing.T) {
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "emoji-d9c7866bb-7v74n",
+				Namespace: "emojivoto",
+				Labels:    map[string]string{"app": "emoji-svc"},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "voting-5f5b555dff-zxl6x",
+				Namespace: "emojivoto",
+				Labels:    map[string]string{"app": "voting-svc"},
+			},
+		},
+	}
+	newService := func(namespace, name string, selector map[string]string) corev1.Service {
+		return corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: corev1.ServiceSpec{
+				Selector: selector,
+				Ports:    []corev1.ServicePort{{Port: 8080}},
+			},
+		}
+	}
+
+	t.Run("Samples services selecting meshed pods", func(t *testing.T) {
+		services := []corev1.Service{
+			newService("emojivoto", "web-svc", map[string]string{"app": "web-svc"}),
+			newService("emojivoto", "voting-svc", map[string]string{"app": "voting-svc"}),
+			newService("emojivoto", "emoji-svc", map[string]string{"app": "emoji-svc"}),
+			newService("emojivoto", "external", nil),
+			newService("other", "emoji-svc", map[string]string{"app": "emoji-svc"}),
+		}
+
+		selecting := servicesSelectingPods(services, pods, 1)
+		if len(selecting) != 1 || selecting[0].service.Name != "emoji-svc" || selecting[0].pod != "emoji-d9c7866bb-7v74n" {
+			t.Fatalf("Expected only emoji-svc to be sampled, got %+v", selecting)
+		}
+
+		selecting = servicesSelectingPods(services, pods, maxEndpointsCheckServices)
+		if len(selecting) != 2 || selecting[1].service.Name != "voting-svc" {
+			t.Fatalf("Expected emoji-svc and voting-svc to be sampled, got %+v", selecting)
+		}
+	})
+
+	t.Run("Returns errors for services with no ready endpoints or failing to resolve", func(t *testing.T) {
+		services := []serviceWithPod{
+			{newService("emojivoto", "emoji-svc", nil), "emoji-d9c7866bb-7v74n"},
+			{newService("emojivoto", "voting-svc", nil), "voting-5f5b555dff-zxl6x"},
+			{newService("emojivoto", "web-svc", nil), "web-5f5b555dff-abcde"},
+		}
+
+		// every lookup waits for the others to be in flight, so that
+		// resolving the services one after the other would time out
+		var arrived sync.WaitGroup
+		arrived.Add(len(services))
+		allArrived := make(chan struct{})
+		go func() {
+			arrived.Wait()
+			close(allArrived)
+		}()
+		client := endpointsClientFunc(func(ctx context.Context, authority string) (*destinationPb.Update, error) {
+			arrived.Done()
+			select {
+			case <-allArrived:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+
+			switch authority {
+			case "emoji-svc.emojivoto.svc.cluster.local:8080":
+				return &destinationPb.Update{Update: &destinationPb.Update_Add{
+					Add: destination.BuildAddrSet(destination.AuthorityEndpoints{
+						Namespace: "emojivoto",
+						ServiceID: "emoji-svc",
+						Pods:      []destination.PodDetails{{Name: "emoji-d9c7866bb-7v74n", IP: 1, Port: 8080}},
+					}),
+				}}, nil
+			case "voting-svc.emojivoto.svc.cluster.local:8080":
+				return &destinationPb.Update{Update: &destinationPb.Update_NoEndpoints{
+					NoEndpoints: &destinationPb.NoEndpoints{Exists: true},
+				}}, nil
+			default:
+				return nil, errors.New("unknown service")
+			}
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		err := checkServicesHaveEndpoints(ctx, client, services, "cluster.local")
+		expectedErrorMsg := "Some services selecting meshed pods have no ready endpoints:\n\t* emojivoto/voting-svc (selecting pod voting-5f5b555dff-zxl6x)\n" +
+			"Some services couldn't be resolved:\n\t* emojivoto/web-svc: unknown service"
+		if err == nil {
+			t.Fatal("Expected error, got nothing")
+		}
+		if err.Error() != expectedErrorMsg {
+			t.Fatalf("Unexpected error message: %s", err.Error())
+		}
+	})
+}
+
+// endpointsClientFunc is a destination client whose Get streams a single
+// update, returned by calling the function with the requested authority
+type endpointsClientFunc func(ctx context.Context, authority string) (*destinationPb.Update, error)
+
+func (f endpointsClientFunc) Get(ctx context.Context, in *destinationPb.GetDestination, _ ...grpc.CallOption) (destinationPb.Destination_GetClient, error) {
+	update, err := f(ctx, in.GetPath())
+	if err != nil {
+		return nil, err
+	}
+	return &singleUpdateGetClient{update: update}, nil
+}
+
+func (f endpointsClientFunc) GetProfile(context.Context, *destinationPb.GetDestination, ...grpc.CallOption) (destinationPb.Destination_GetProfileClient, error) {
+	return nil, errors.New("Not implemented")
+}
+
+type singleUpdateGetClient struct {
+	update *destinationPb.Update
+	grpc.ClientStream
+}
+
+func (c *singleUpdateGetClient) Recv() (*destinationPb.Update, error) {
+	return c.update, nil
+}
+
+func TestValidateDestinationSelfCheck(t *testing.T) {
+	testCases := []struct {
+		result   *destination.SelfCheckResult
+		err      error
+		expected string
+		skipped  bool
+	}{
+		{
+			&destination.SelfCheckResult{InformersSynced: true, WatchedServices: 3},
+			nil,
+			"",
+			false,
+		},
+		{
+			&destination.SelfCheckResult{InformersSynced: false, WatchedServices: 3},
+			nil,
+			"The destination informers aren't synced yet (watching 3 services)",
+			false,
+		},
+		{
+			nil,
+			errors.New("connection refused"),
+			"failed to fetch the destination self-check: connection refused",
+			false,
+		},
+		{
+			nil,
+			destination.ErrSelfCheckNotFound,
+			destinationSelfCheckUnavailableSkipReason,
+			true,
+		},
+	}
+
+	for i, tc := range testCases {
+		tc := tc // pin
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			err := validateDestinationSelfCheck(tc.result, tc.err)
+			if tc.expected == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			var se *SkipError
+			if isSkip := errors.As(err, &se); isSkip != tc.skipped {
+				t.Fatalf("Expected skipped to be %t, got error %v", tc.skipped, err)
+			}
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("Expected error %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
 func TestLinkerdPreInstallGlobalResourcesChecks(t *testing.T) {
 	hc := NewHealthChecker(
 		[]CategoryID{LinkerdPreInstallGlobalResourcesChecks},
@@ -275,6 +275,10 @@ func (conf *ResourceConfig) GetPodPatch(injectProxy bool) ([]byte, error) {
 		return nil, fmt.Errorf("%s cannot be set when identity is disabled", k8s.ProxyRequireIdentityOnInboundPortsAnnotation)
 	}
 
+	if err := validateResourceAnnotations(conf.getOverrideAnnotations()); err != nil {
+		return nil, err
+	}
+
 	if values.ClusterNetworks != "" {
 		for _, network := range strings.Split(strings.Trim(values.ClusterNetworks, ","), ",") {
 			if _, _, err := net.ParseCIDR(network); err != nil {
@@ -744,7 +748,9 @@ func (conf *ResourceConfig) injectPodAnnotations(values *podPatch) {
 	}
 }
 
-func (conf *ResourceConfig) applyAnnotationOverrides(values *l5dcharts.Values) {
+// getOverrideAnnotations returns the annotations whose values override the
+// default configuration
+func (conf *ResourceConfig) getOverrideAnnotations() map[string]string {
 	annotations := make(map[string]string)
 	for k, v := range conf.pod.meta.Annotations {
 		annotations[k] = v
@@ -759,6 +765,30 @@ func (conf *ResourceConfig) applyAnnotationOverrides(values *l5dcharts.Values) {
 			annotations[k] = v
 		}
 	}
+	return annotations
+}
+
+// validateResourceAnnotations checks that the proxy resources annotations
+// are valid quantities, so that pods with invalid overrides are rejected
+// instead of silently falling back to the default resources
+func validateResourceAnnotations(annotations map[string]string) error {
+	for _, annotation := range []string{
+		k8s.ProxyCPURequestAnnotation,
+		k8s.ProxyMemoryRequestAnnotation,
+		k8s.ProxyCPULimitAnnotation,
+		k8s.ProxyMemoryLimitAnnotation,
+	} {
+		if value, ok := annotations[annotation]; ok {
+			if _, err := k8sResource.ParseQuantity(value); err != nil {
+				return fmt.Errorf("invalid value '%s' for the %s annotation: %s", value, annotation, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (conf *ResourceConfig) applyAnnotationOverrides(values *l5dcharts.Values) {
+	annotations := conf.getOverrideAnnotations()
 
 	if override, ok := annotations[k8s.ProxyInjectAnnotation]; ok {
 		if override == k8s.ProxyInjectIngress {
@@ -1,6 +1,7 @@
 package inject
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -290,6 +291,69 @@ func TestGetOverriddenValues(t *testing.T) {
 	}
 }
 
+func TestGetPodPatchInvalidResourceAnnotations(t *testing.T) {
+	testConfig, err := l5dcharts.NewValues()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	testConfig.Proxy.DisableIdentity = true
+
+	var testCases = []struct {
+		annotations map[string]string
+		expectedErr string
+	}{
+		{
+			annotations: map[string]string{
+				k8s.ProxyCPURequestAnnotation:    "100m",
+				k8s.ProxyMemoryRequestAnnotation: "20Mi",
+				k8s.ProxyCPULimitAnnotation:      "1",
+				k8s.ProxyMemoryLimitAnnotation:   "250Mi",
+			},
+		},
+		{
+			annotations: map[string]string{k8s.ProxyCPULimitAnnotation: "lots"},
+			expectedErr: "invalid value 'lots' for the config.linkerd.io/proxy-cpu-limit annotation: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'",
+		},
+		{
+			annotations: map[string]string{k8s.ProxyMemoryRequestAnnotation: "20 MB"},
+			expectedErr: "invalid value '20 MB' for the config.linkerd.io/proxy-memory-request annotation: quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'",
+		},
+	}
+
+	for i, tc := range testCases {
+		tc := tc // pin
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			data, err := yaml.Marshal(&corev1.Pod{
+				TypeMeta: metav1.TypeMeta{Kind: "Pod", APIVersion: "v1"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "web",
+					Annotations: tc.annotations,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "web"}},
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resourceConfig := NewResourceConfig(testConfig, OriginWebhook).WithKind("Pod")
+			if err := resourceConfig.parse(data); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = resourceConfig.GetPodPatch(true)
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+			} else if err == nil || err.Error() != tc.expectedErr {
+				t.Fatalf("Expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
 func TestWholeCPUCores(t *testing.T) {
 	for _, c := range []struct {
 		v string
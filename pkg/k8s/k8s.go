@@ -110,9 +110,11 @@ var resourceNames = []resourceName{
 }
 
 // GetConfig returns kubernetes config based on the current environment.
-// If fpath is provided, loads configuration from that file. Otherwise,
-// GetConfig uses default strategy to load configuration from $KUBECONFIG,
-// .kube/config, or just returns in-cluster config.
+// If fpath is provided, loads configuration from that file, ignoring
+// $KUBECONFIG. Otherwise, GetConfig uses default strategy to load
+// configuration from $KUBECONFIG (merging all the files it lists, with the
+// first file to set a value taking precedence), .kube/config, or just returns
+// in-cluster config.
 func GetConfig(fpath, kubeContext string) (*rest.Config, error) {
 	rules := clientcmd.NewDefaultClientConfigLoadingRules()
 	if fpath != "" {
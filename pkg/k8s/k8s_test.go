@@ -1,6 +1,8 @@
 package k8s
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -23,6 +25,86 @@ func TestGetConfig(t *testing.T) {
 			t.Fatalf("Expecting error when config file does not exist, got nothing")
 		}
 	})
+
+	mergedConfig := filepath.Join("testdata", "config_merge_a.test") + string(os.PathListSeparator) + filepath.Join("testdata", "config_merge_b.test")
+
+	testCases := []struct {
+		name         string
+		kubeconfig   string
+		fpath        string
+		kubeContext  string
+		expectedHost string
+	}{
+		{
+			"Uses the current context of the first file in $KUBECONFIG",
+			mergedConfig,
+			"",
+			"",
+			"https://10.10.0.1",
+		},
+		{
+			"Merges contexts from all the files in $KUBECONFIG",
+			mergedConfig,
+			"",
+			"contextB",
+			"https://10.10.0.2",
+		},
+		{
+			"Explicit path overrides $KUBECONFIG",
+			mergedConfig,
+			"testdata/config.test",
+			"",
+			"https://55.197.171.239",
+		},
+		{
+			"Explicit path overrides a $KUBECONFIG pointing to missing files",
+			"/this/doest./not/exist.config",
+			"testdata/config.test",
+			"cluster2",
+			"https://30.88.172.234",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.name, func(t *testing.T) {
+			setKubeconfigEnv(t, tc.kubeconfig)
+
+			config, err := GetConfig(tc.fpath, tc.kubeContext)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if config.Host != tc.expectedHost {
+				t.Fatalf("Expected host to be [%s] got [%s]", tc.expectedHost, config.Host)
+			}
+		})
+	}
+
+	t.Run("Returns error if the context isn't in any of the $KUBECONFIG files", func(t *testing.T) {
+		setKubeconfigEnv(t, mergedConfig)
+
+		_, err := GetConfig("", "cluster1")
+		if err == nil {
+			t.Fatalf("Expecting error when context does not exist, got nothing")
+		}
+	})
+}
+
+// setKubeconfigEnv sets $KUBECONFIG for the duration of the test
+func setKubeconfigEnv(t *testing.T, value string) {
+	t.Helper()
+	previous, isSet := os.LookupEnv("KUBECONFIG")
+	if err := os.Setenv("KUBECONFIG", value); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		if isSet {
+			os.Setenv("KUBECONFIG", previous)
+		} else {
+			os.Unsetenv("KUBECONFIG")
+		}
+	})
 }
 
 func TestCanonicalResourceNameFromFriendlyName(t *testing.T) {
@@ -248,6 +248,10 @@ const (
 	// configured for the Pod
 	ProxyWaitBeforeExitSecondsAnnotation = ProxyConfigAnnotationsPrefixAlpha + "/proxy-wait-before-exit-seconds"
 
+	// EnableH2UpgradeAnnotation can be set on a Service to override the
+	// destination controller's enable-h2-upgrade setting for its endpoints.
+	EnableH2UpgradeAnnotation = ProxyConfigAnnotationsPrefix + "/enable-h2-upgrade"
+
 	// ProxyAwait can be used to force the application to wait for the proxy
 	// to be ready.
 	ProxyAwait = ProxyConfigAnnotationsPrefix + "/proxy-await"
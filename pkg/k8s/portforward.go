@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -95,6 +96,12 @@ func NewPortForward(
 	}
 
 	if podName == "" {
+		// only check the namespace here, to give a clearer error without an
+		// extra API call on the happy path
+		exists, err := k8sAPI.NamespaceExists(ctx, namespace)
+		if err == nil && !exists {
+			return nil, fmt.Errorf("no running pods found for %s: the \"%s\" namespace does not exist", deployName, namespace)
+		}
 		return nil, fmt.Errorf("no running pods found for %s", deployName)
 	}
 
@@ -226,12 +233,13 @@ func (pf *PortForward) GetStop() <-chan struct{} {
 
 // URLFor returns the URL for the port-forward connection.
 func (pf *PortForward) URLFor(path string) string {
-	return fmt.Sprintf("http://%s:%d%s", pf.host, pf.localPort, path)
+	return fmt.Sprintf("http://%s%s", pf.AddressAndPort(), path)
 }
 
 // AddressAndPort returns the address and port for the port-forward connection.
+// IPv6 addresses are enclosed in square brackets.
 func (pf *PortForward) AddressAndPort() string {
-	return fmt.Sprintf("%s:%d", pf.host, pf.localPort)
+	return net.JoinHostPort(pf.host, strconv.Itoa(pf.localPort))
 }
 
 // getEphemeralPort selects a port for the port-forwarding. It binds to a free
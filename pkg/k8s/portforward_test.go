@@ -79,7 +79,12 @@ func TestNewPortForward(t *testing.T) {
 		{
 			"pod-ns",
 			"deploy-name",
-			[]string{`apiVersion: v1
+			[]string{
+				`apiVersion: v1
+kind: Namespace
+metadata:
+  name: pod-ns`,
+				`apiVersion: v1
 kind: Pod
 metadata:
   name: bad-name
@@ -92,7 +97,12 @@ status:
 		{
 			"pod-ns",
 			"deploy-name",
-			[]string{`apiVersion: v1
+			[]string{
+				`apiVersion: v1
+kind: Namespace
+metadata:
+  name: pod-ns`,
+				`apiVersion: v1
 kind: Pod
 metadata:
   name: deploy-name-foo-bar
@@ -105,7 +115,12 @@ status:
 		{
 			"pod-ns",
 			"deploy-name",
-			[]string{`apiVersion: v1
+			[]string{
+				`apiVersion: v1
+kind: Namespace
+metadata:
+  name: pod-ns`,
+				`apiVersion: v1
 kind: Pod
 metadata:
   name: deploy-name-foo-bar
@@ -115,6 +130,12 @@ status:
 			},
 			errors.New("no running pods found for deploy-name"),
 		},
+		{
+			"pod-ns",
+			"deploy-name",
+			[]string{},
+			errors.New("no running pods found for deploy-name: the \"pod-ns\" namespace does not exist"),
+		},
 	}
 
 	for i, test := range tests {
@@ -135,3 +156,85 @@ status:
 		})
 	}
 }
+
+func TestNewPortForwardURL(t *testing.T) {
+	tests := []struct {
+		kubeContext string
+		expectedURL string
+	}{
+		{
+			"cluster1",
+			"https://55.197.171.239/api/v1/namespaces/pod-ns/pods/pod-name/portforward",
+		},
+		{
+			"clusterTrailingSlash",
+			"https://162.128.50.11/api/v1/namespaces/pod-ns/pods/pod-name/portforward",
+		},
+		{
+			"clusterWithPath",
+			"https://162.128.50.12/k8s/clusters/c-fhjws/api/v1/namespaces/pod-ns/pods/pod-name/portforward",
+		},
+	}
+
+	for _, test := range tests {
+		test := test // pin
+		t.Run(test.kubeContext, func(t *testing.T) {
+			k8sAPI, err := NewAPI("testdata/config.test", test.kubeContext, "", []string{}, 0)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			pf, err := newPortForward(k8sAPI, "pod-ns", "pod-name", "localhost", 1234, 8084, false)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			if pf.url.String() != test.expectedURL {
+				t.Fatalf("Expected URL [%s], got [%s]", test.expectedURL, pf.url.String())
+			}
+		})
+	}
+}
+
+func TestPortForwardURLFor(t *testing.T) {
+	tests := []struct {
+		host               string
+		expectedAddress    string
+		expectedURLForPath string
+	}{
+		{
+			"localhost",
+			"localhost:1234",
+			"http://localhost:1234/api/v1/",
+		},
+		{
+			"127.0.0.1",
+			"127.0.0.1:1234",
+			"http://127.0.0.1:1234/api/v1/",
+		},
+		{
+			"::1",
+			"[::1]:1234",
+			"http://[::1]:1234/api/v1/",
+		},
+		{
+			"fe80::1ff:fe23:4567:890a",
+			"[fe80::1ff:fe23:4567:890a]:1234",
+			"http://[fe80::1ff:fe23:4567:890a]:1234/api/v1/",
+		},
+	}
+
+	for _, test := range tests {
+		test := test // pin
+		t.Run(test.host, func(t *testing.T) {
+			pf := &PortForward{host: test.host, localPort: 1234}
+
+			if address := pf.AddressAndPort(); address != test.expectedAddress {
+				t.Fatalf("Expected address [%s], got [%s]", test.expectedAddress, address)
+			}
+			if url := pf.URLFor("/api/v1/"); url != test.expectedURLForPath {
+				t.Fatalf("Expected URL [%s], got [%s]", test.expectedURLForPath, url)
+			}
+		})
+	}
+}
@@ -29,24 +29,34 @@ func RenderOpenAPI(fileName, namespace, name, clusterDomain string, w io.Writer)
 		return err
 	}
 
+	profile, err := RenderProfileFromOpenAPI(input, namespace, name, clusterDomain)
+	if err != nil {
+		return err
+	}
+
+	return writeProfile(profile, w)
+}
+
+// RenderProfileFromOpenAPI reads an OpenAPI spec in YAML or JSON format and
+// returns the corresponding ServiceProfile, with a route for each path and
+// method pair, given a namespace, service, and cluster domain.
+func RenderProfileFromOpenAPI(input io.Reader, namespace, name, clusterDomain string) (sp.ServiceProfile, error) {
 	bytes, err := ioutil.ReadAll(input)
 	if err != nil {
-		return fmt.Errorf("Error reading file: %s", err)
+		return sp.ServiceProfile{}, fmt.Errorf("Error reading file: %s", err)
 	}
 	json, err := yaml.YAMLToJSON(bytes)
 	if err != nil {
-		return fmt.Errorf("Error parsing yaml: %s", err)
+		return sp.ServiceProfile{}, fmt.Errorf("Error parsing yaml: %s", err)
 	}
 
 	swagger := spec.Swagger{}
 	err = swagger.UnmarshalJSON(json)
 	if err != nil {
-		return fmt.Errorf("Error parsing OpenAPI spec: %s", err)
+		return sp.ServiceProfile{}, fmt.Errorf("Error parsing OpenAPI spec: %s", err)
 	}
 
-	profile := swaggerToServiceProfile(swagger, namespace, name, clusterDomain)
-
-	return writeProfile(profile, w)
+	return swaggerToServiceProfile(swagger, namespace, name, clusterDomain), nil
 }
 
 func swaggerToServiceProfile(swagger spec.Swagger, namespace, name, clusterDomain string) sp.ServiceProfile {
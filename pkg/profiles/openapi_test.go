@@ -1,6 +1,7 @@
 package profiles
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/go-openapi/spec"
@@ -79,3 +80,71 @@ func TestSwaggerToServiceProfile(t *testing.T) {
 		t.Fatalf("ServiceProfiles are not equal: %v", err)
 	}
 }
+
+func TestRenderProfileFromOpenAPI(t *testing.T) {
+	namespace := "myns"
+	name := "mysvc"
+	clusterDomain := "mycluster.local"
+
+	input := `swagger: "2.0"
+basePath: /api
+paths:
+  /books/{id}:
+    get:
+      responses:
+        200: {}
+    delete: {}
+`
+
+	expectedServiceProfile := sp.ServiceProfile{
+		TypeMeta: ServiceProfileMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name + "." + namespace + ".svc." + clusterDomain,
+			Namespace: namespace,
+		},
+		Spec: sp.ServiceProfileSpec{
+			Routes: []*sp.RouteSpec{
+				{
+					Name: "DELETE /api/books/{id}",
+					Condition: &sp.RequestMatch{
+						PathRegex: "/api/books/[^/]*",
+						Method:    "DELETE",
+					},
+				},
+				{
+					Name: "GET /api/books/{id}",
+					Condition: &sp.RequestMatch{
+						PathRegex: "/api/books/[^/]*",
+						Method:    "GET",
+					},
+					ResponseClasses: []*sp.ResponseClass{
+						{
+							Condition: &sp.ResponseMatch{
+								Status: &sp.Range{
+									Min: 200,
+									Max: 200,
+								},
+							},
+							IsFailure: false,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	actualServiceProfile, err := RenderProfileFromOpenAPI(strings.NewReader(input), namespace, name, clusterDomain)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err = ServiceProfileYamlEquals(actualServiceProfile, expectedServiceProfile)
+	if err != nil {
+		t.Fatalf("ServiceProfiles are not equal: %v", err)
+	}
+
+	_, err = RenderProfileFromOpenAPI(strings.NewReader("paths: ["), namespace, name, clusterDomain)
+	if err == nil {
+		t.Fatal("Expected an error for an invalid spec, got nothing")
+	}
+}
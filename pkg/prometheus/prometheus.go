@@ -89,13 +89,14 @@ func init() {
 	)
 }
 
-// NewGrpcServer returns a grpc server pre-configured with prometheus interceptors and oc-grpc handler
-func NewGrpcServer() *grpc.Server {
-	server := grpc.NewServer(
+// NewGrpcServer returns a grpc server pre-configured with prometheus interceptors and oc-grpc handler,
+// and configured with any additional opt
+func NewGrpcServer(opt ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(append([]grpc.ServerOption{
 		grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
 		grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
 		grpc.StatsHandler(&ocgrpc.ServerHandler{}),
-	)
+	}, opt...)...)
 
 	grpc_prometheus.EnableHandlingTimeHistogram()
 	grpc_prometheus.Register(server)
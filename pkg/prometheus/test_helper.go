@@ -17,6 +17,8 @@ import (
 // TODO: move this into something shared under /controller, or into /pkg
 type MockProm struct {
 	Res             model.Value
+	FlagsRes        promv1.FlagsResult
+	FlagsCalls      int      // expose the number of Flags calls, to test caching
 	QueriesExecuted []string // expose the queries our Mock Prometheus receives, to test query generation
 	rwLock          sync.Mutex
 }
@@ -66,7 +68,10 @@ func (m *MockProm) DeleteSeries(ctx context.Context, matches []string, startTime
 
 // Flags returns the flag values that Prometheus was launched with.
 func (m *MockProm) Flags(ctx context.Context) (promv1.FlagsResult, error) {
-	return promv1.FlagsResult{}, nil
+	m.rwLock.Lock()
+	defer m.rwLock.Unlock()
+	m.FlagsCalls++
+	return m.FlagsRes, nil
 }
 
 // LabelValues performs a query for the values of the given label, time range and matchers.
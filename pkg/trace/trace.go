@@ -1,22 +1,197 @@
 package trace
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"contrib.go.opencensus.io/exporter/jaeger"
 	"contrib.go.opencensus.io/exporter/ocagent"
+	log "github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// BackendOC exports traces to an OpenCensus agent (default)
+	BackendOC = "oc"
+	// BackendJaeger exports traces directly to a Jaeger collector
+	BackendJaeger = "jaeger"
 )
 
+// Options holds the settings used to configure the trace exporter
+type Options struct {
+	// ServiceName is the name reported for the traced process
+	ServiceName string
+	// Address is the endpoint of the collector. For the Jaeger backend this
+	// is the URL of the collector's HTTP endpoint, e.g.
+	// http://jaeger.linkerd-jaeger:14268/api/traces
+	Address string
+	// TLSCAPath is the path to a CA certificate used to verify the OpenCensus
+	// collector. When empty, the connection to the collector is insecure. It
+	// can't be set for the Jaeger backend.
+	TLSCAPath string
+	// Backend is the kind of collector traces are exported to; one of
+	// BackendOC or BackendJaeger. Defaults to BackendOC.
+	Backend string
+}
+
 // InitializeTracing initiates trace, exporter and the sampler
 func InitializeTracing(serviceName string, address string) error {
-	oce, err := ocagent.NewExporter(
-		ocagent.WithInsecure(),
-		ocagent.WithAddress(address),
-		ocagent.WithServiceName(serviceName))
-	if err != nil {
-		return err
+	return InitializeTracingWithOptions(Options{
+		ServiceName: serviceName,
+		Address:     address,
+	})
+}
+
+// InitializeTracingWithOptions initiates trace, exporter and the sampler
+// according to the given options
+func InitializeTracingWithOptions(opts Options) error {
+	switch opts.Backend {
+	case "", BackendOC:
+		oce, err := newOCExporter(opts)
+		if err != nil {
+			return err
+		}
+		register(oce, func() {
+			if err := oce.Stop(); err != nil {
+				log.Errorf("failed to stop the trace exporter: %s", err)
+			}
+		})
+	case BackendJaeger:
+		je, err := newJaegerExporter(opts)
+		if err != nil {
+			return err
+		}
+		register(je, je.Flush)
+	default:
+		return fmt.Errorf("unsupported trace backend: %s", opts.Backend)
+	}
+
+	return SetSamplingProbability(1.0)
+}
+
+func newOCExporter(opts Options) (*ocagent.Exporter, error) {
+	exporterOpts := []ocagent.ExporterOption{
+		ocagent.WithAddress(opts.Address),
+		ocagent.WithServiceName(opts.ServiceName),
+	}
+
+	if opts.TLSCAPath != "" {
+		creds, err := credentials.NewClientTLSFromFile(opts.TLSCAPath, "")
+		if err != nil {
+			return nil, err
+		}
+		exporterOpts = append(exporterOpts, ocagent.WithTLSCredentials(creds))
+	} else {
+		exporterOpts = append(exporterOpts, ocagent.WithInsecure())
+	}
+
+	return ocagent.NewExporter(exporterOpts...)
+}
+
+// newJaegerExporter returns an exporter sending spans to the HTTP endpoint of
+// a Jaeger collector. The exporter always uses the default HTTP client, so a
+// custom CA can't be used to verify the collector.
+func newJaegerExporter(opts Options) (*jaeger.Exporter, error) {
+	if opts.TLSCAPath != "" {
+		return nil, errors.New("a TLS CA can't be set for the jaeger trace backend")
+	}
+
+	return jaeger.NewExporter(jaeger.Options{
+		CollectorEndpoint: opts.Address,
+		Process: jaeger.Process{
+			ServiceName: opts.ServiceName,
+		},
+		OnError: func(err error) {
+			log.Warnf("failed to export spans to Jaeger: %s", err)
+		},
+	})
+}
+
+var (
+	exporter      trace.Exporter
+	stopExporter  func()
+	exporterMutex sync.Mutex
+)
+
+func register(e trace.Exporter, stop func()) {
+	trace.RegisterExporter(e)
+
+	exporterMutex.Lock()
+	exporter = e
+	stopExporter = stop
+	exporterMutex.Unlock()
+}
+
+// Flush stops the exporter registered by InitializeTracingWithOptions, if
+// any, after sending its pending spans. It's meant to be called on shutdown,
+// in particular by short-lived processes that would otherwise exit before
+// their spans are exported.
+func Flush() {
+	exporterMutex.Lock()
+	e, stop := exporter, stopExporter
+	exporter, stopExporter = nil, nil
+	exporterMutex.Unlock()
+
+	if e != nil {
+		trace.UnregisterExporter(e)
+		stop()
+	}
+}
+
+var (
+	samplingProbability      = 1.0
+	samplingProbabilityMutex sync.Mutex
+)
+
+// SetSamplingProbability reconfigures the sampler of the running process so
+// that traces are sampled with the given probability, which must be within
+// [0, 1]
+func SetSamplingProbability(probability float64) error {
+	if probability < 0 || probability > 1 {
+		return fmt.Errorf("sampling probability must be between 0 and 1, was %v", probability)
 	}
-	trace.RegisterExporter(oce)
+
+	samplingProbabilityMutex.Lock()
+	defer samplingProbabilityMutex.Unlock()
+
+	samplingProbability = probability
 	trace.ApplyConfig(trace.Config{
-		DefaultSampler: trace.AlwaysSample(),
+		DefaultSampler: trace.ProbabilitySampler(probability),
 	})
 	return nil
 }
+
+// SamplingHandler returns an http.Handler that reports the current sampling
+// probability on GET, and updates it on PUT or POST from the `probability`
+// form value, e.g. `curl -X PUT localhost:9996/tracing/sampling?probability=1`
+func SamplingHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			probability, err := strconv.ParseFloat(req.FormValue("probability"), 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid probability: %s", err), http.StatusBadRequest)
+				return
+			}
+			if err := SetSamplingProbability(probability); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			log.Infof("trace sampling probability set to %v", probability)
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		samplingProbabilityMutex.Lock()
+		probability := samplingProbability
+		samplingProbabilityMutex.Unlock()
+		fmt.Fprintf(w, "%v\n", probability)
+	})
+}
@@ -0,0 +1,111 @@
+package trace
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/uber/jaeger-client-go/thrift"
+	"github.com/uber/jaeger-client-go/thrift-gen/jaeger"
+	"go.opencensus.io/trace"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	testCases := []struct {
+		method       string
+		url          string
+		expectedCode int
+		expectedBody string
+	}{
+		{http.MethodPut, "/tracing/sampling?probability=0.5", http.StatusOK, "0.5\n"},
+		{http.MethodGet, "/tracing/sampling", http.StatusOK, "0.5\n"},
+		{http.MethodPost, "/tracing/sampling?probability=1", http.StatusOK, "1\n"},
+		{http.MethodPut, "/tracing/sampling?probability=2", http.StatusBadRequest, "sampling probability must be between 0 and 1, was 2\n"},
+		{http.MethodPut, "/tracing/sampling?probability=foo", http.StatusBadRequest, "invalid probability: strconv.ParseFloat: parsing \"foo\": invalid syntax\n"},
+		{http.MethodDelete, "/tracing/sampling", http.StatusMethodNotAllowed, "method not allowed\n"},
+		{http.MethodGet, "/tracing/sampling", http.StatusOK, "1\n"},
+	}
+
+	handler := SamplingHandler()
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.method+" "+tc.url, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.url, nil))
+
+			if rec.Code != tc.expectedCode {
+				t.Fatalf("Expected status code %d, got %d", tc.expectedCode, rec.Code)
+			}
+			if rec.Body.String() != tc.expectedBody {
+				t.Fatalf("Expected body %q, got %q", tc.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestJaegerBackend(t *testing.T) {
+	t.Run("Exports spans to the collector on Flush", func(t *testing.T) {
+		type request struct {
+			contentType string
+			body        []byte
+		}
+		requests := make(chan request, 1)
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("Failed to read request body: %s", err)
+			}
+			requests <- request{r.Header.Get("Content-Type"), body}
+		}))
+		defer ts.Close()
+
+		err := InitializeTracingWithOptions(Options{
+			ServiceName: "linkerd-test",
+			Address:     ts.URL + "/api/traces",
+			Backend:     BackendJaeger,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		_, span := trace.StartSpan(context.Background(), "test-span")
+		span.End()
+		Flush()
+
+		req := <-requests
+		if req.contentType != "application/x-thrift" {
+			t.Fatalf("Expected a thrift request, got content type %q", req.contentType)
+		}
+
+		buf := thrift.NewTMemoryBuffer()
+		if _, err := buf.Write(req.body); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		batch := &jaeger.Batch{}
+		if err := batch.Read(thrift.NewTBinaryProtocolTransport(buf)); err != nil {
+			t.Fatalf("Failed to decode the batch: %s", err)
+		}
+
+		if batch.GetProcess().GetServiceName() != "linkerd-test" {
+			t.Fatalf("Expected service name linkerd-test, got %q", batch.GetProcess().GetServiceName())
+		}
+		if len(batch.GetSpans()) != 1 || batch.GetSpans()[0].GetOperationName() != "test-span" {
+			t.Fatalf("Expected a single span named test-span, got %v", batch.GetSpans())
+		}
+	})
+
+	t.Run("Fails with a TLS CA", func(t *testing.T) {
+		err := InitializeTracingWithOptions(Options{
+			ServiceName: "linkerd-test",
+			Address:     "http://localhost:14268/api/traces",
+			TLSCAPath:   "/var/run/linkerd/ca.crt",
+			Backend:     BackendJaeger,
+		})
+		expected := "a TLS CA can't be set for the jaeger trace backend"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected error %q, got %v", expected, err)
+		}
+	})
+}
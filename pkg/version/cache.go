@@ -0,0 +1,77 @@
+package version
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CacheTTL is how long the latest versions retrieved by GetLatestVersions are
+// cached on disk, so that running checks repeatedly doesn't hit the version
+// check endpoint every time. A zero value disables the cache.
+var CacheTTL = 5 * time.Minute
+
+type versionCache struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Versions  map[string]string `json:"versions"`
+}
+
+// cachePath returns the location of the versions cache file, under the
+// user's cache directory
+func cachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "linkerd", "versions.json"), nil
+}
+
+// readCache returns the versions cached in path, if they were cached less
+// than ttl before now
+func readCache(path string, ttl time.Duration, now time.Time) (Channels, bool) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Channels{}, false
+	}
+
+	var cache versionCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		log.Debugf("ignoring invalid versions cache %s: %s", path, err)
+		return Channels{}, false
+	}
+	if now.Sub(cache.Timestamp) >= ttl || now.Before(cache.Timestamp) {
+		return Channels{}, false
+	}
+
+	channels, err := parseVersions(cache.Versions)
+	if err != nil {
+		log.Debugf("ignoring invalid versions cache %s: %s", path, err)
+		return Channels{}, false
+	}
+	return channels, true
+}
+
+// writeCache stores the given versions in path, along with the time they
+// were retrieved
+func writeCache(path string, channels Channels, now time.Time) error {
+	cache := versionCache{
+		Timestamp: now,
+		Versions:  make(map[string]string),
+	}
+	for _, cv := range channels.array {
+		cache.Versions[cv.channel] = cv.String()
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
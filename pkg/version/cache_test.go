@@ -0,0 +1,57 @@
+package version
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVersionCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "linkerd-version-cache")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "linkerd", "versions.json")
+
+	channels := Channels{
+		[]channelVersion{
+			{"stable", "2.1.0"},
+			{"edge", "2.1.0"},
+		},
+	}
+	now := time.Now()
+
+	if _, ok := readCache(path, time.Minute, now); ok {
+		t.Fatal("Expected a cache miss before writing the cache")
+	}
+
+	if err := writeCache(path, channels, now); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		name     string
+		now      time.Time
+		expected bool
+	}{
+		{"fresh", now.Add(30 * time.Second), true},
+		{"expired", now.Add(time.Minute), false},
+		{"in the future", now.Add(-time.Second), false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.name, func(t *testing.T) {
+			cached, ok := readCache(path, time.Minute, tc.now)
+			if ok != tc.expected {
+				t.Fatalf("Expected cache hit to be %t, got %t", tc.expected, ok)
+			}
+			if ok && !channelsEqual(cached, channels) {
+				t.Fatalf("Expected cached versions \"%s\", got \"%s\"", channels, cached)
+			}
+		})
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
 )
 
 // Channels provides an interface to interact with a set of release channels.
@@ -58,10 +61,30 @@ func (c Channels) Match(actualVersion string) error {
 }
 
 // GetLatestVersions performs an online request to check for the latest Linkerd
-// release channels.
+// release channels. Results are cached on disk for CacheTTL.
 func GetLatestVersions(ctx context.Context, uuid string, source string) (Channels, error) {
+	var path string
+	if CacheTTL > 0 {
+		var err error
+		if path, err = cachePath(); err != nil {
+			log.Debugf("versions cache disabled: %s", err)
+		} else if channels, ok := readCache(path, CacheTTL, time.Now()); ok {
+			return channels, nil
+		}
+	}
+
 	url := fmt.Sprintf("%s?version=%s&uuid=%s&source=%s", CheckURL, Version, uuid, source)
-	return getLatestVersions(ctx, http.DefaultClient, url)
+	channels, err := getLatestVersions(ctx, http.DefaultClient, url)
+	if err != nil {
+		return Channels{}, err
+	}
+
+	if path != "" {
+		if err := writeCache(path, channels, time.Now()); err != nil {
+			log.Debugf("failed to write versions cache %s: %s", path, err)
+		}
+	}
+	return channels, nil
 }
 
 func getLatestVersions(ctx context.Context, client *http.Client, url string) (Channels, error) {
@@ -91,6 +114,12 @@ func getLatestVersions(ctx context.Context, client *http.Client, url string) (Ch
 		return Channels{}, err
 	}
 
+	return parseVersions(versionRsp)
+}
+
+// parseVersions builds a Channels from a versioncheck response, mapping
+// channel names to channel-version strings
+func parseVersions(versionRsp map[string]string) (Channels, error) {
 	channels := Channels{}
 	for c, v := range versionRsp {
 		cv, err := parseChannelVersion(v)
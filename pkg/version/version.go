@@ -12,6 +12,11 @@ import (
 // DO NOT EDIT
 var Version = undefinedVersion
 
+// GitSHA is the git commit the current process was built from. It's set at
+// link time for the CLI, and from `$LINKERD_CONTAINER_GIT_SHA_OVERRIDE` for the
+// container images. It's empty when the build didn't provide it.
+var GitSHA = ""
+
 // ProxyInitVersion is the pinned version of the proxy-init, from
 // https://github.com/linkerd/linkerd2-proxy-init
 // This has to be kept in sync with the constraint version for
@@ -36,6 +41,9 @@ func init() {
 			Version = override
 		}
 	}
+	if GitSHA == "" {
+		GitSHA = os.Getenv("LINKERD_CONTAINER_GIT_SHA_OVERRIDE")
+	}
 }
 
 // match compares two versions and returns success if they match, or an error
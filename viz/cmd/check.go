@@ -78,6 +78,8 @@ func configureAndRunChecks(wout io.Writer, werr io.Writer, options *checkOptions
 		Impersonate:           impersonate,
 		ImpersonateGroup:      impersonateGroup,
 		APIAddr:               apiAddr,
+		APITimeout:            apiTimeout,
+		TraceAPIRequests:      traceCollector != "",
 		RetryDeadline:         time.Now().Add(options.wait),
 		DataPlaneNamespace:    options.namespace,
 	})
@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/viz/pkg/api"
 	"github.com/pkg/browser"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // These constants are used by the `show` flag.
@@ -36,6 +40,10 @@ const (
 
 	// defaultPort is for port-forwarding via `linkerd dashboard`
 	defaultPort = 50750
+
+	// grafanaAddrArg is the web container's flag pointing at the Grafana
+	// instance it proxies /grafana to, either bundled or external
+	grafanaAddrArg = "-grafana-addr="
 )
 
 // dashboardOptions holds values for command line flags that apply to the dashboard
@@ -90,6 +98,8 @@ func NewCmdDashboard() *cobra.Command {
 				ImpersonateGroup:      impersonateGroup,
 				KubeContext:           kubeContext,
 				APIAddr:               apiAddr,
+				APITimeout:            apiTimeout,
+				TraceAPIRequests:      traceCollector != "",
 				RetryDeadline:         time.Now().Add(options.wait),
 			}, true)
 
@@ -103,6 +113,20 @@ func NewCmdDashboard() *cobra.Command {
 				return err
 			}
 
+			// Grafana is assumed to be enabled unless the web deployment says
+			// otherwise, so that lacking permissions to read it doesn't
+			// prevent opening the dashboard
+			grafanaEnabled := true
+			web, err := k8sAPI.AppsV1().Deployments(vizNs.Name).Get(cmd.Context(), webDeployment, metav1.GetOptions{})
+			if err != nil {
+				log.Debugf("Failed to get the %s deployment, assuming Grafana is enabled: %s", webDeployment, err)
+			} else {
+				grafanaEnabled = getGrafanaAddr(web) != ""
+			}
+			if options.show == showGrafana && !grafanaEnabled {
+				return fmt.Errorf("no Grafana instance is configured; set grafanaUrl or grafana.enabled when installing linkerd-viz")
+			}
+
 			signals := make(chan os.Signal, 1)
 			signal.Notify(signals, os.Interrupt)
 			defer signal.Stop(signals)
@@ -137,7 +161,9 @@ func NewCmdDashboard() *cobra.Command {
 			grafanaURL := portforward.URLFor("/grafana")
 
 			fmt.Printf("Linkerd dashboard available at:\n%s\n", webURL)
-			fmt.Printf("Grafana dashboard available at:\n%s\n", grafanaURL)
+			if grafanaEnabled {
+				fmt.Printf("Grafana dashboard available at:\n%s\n", grafanaURL)
+			}
 
 			switch options.show {
 			case showLinkerd:
@@ -173,3 +199,17 @@ func NewCmdDashboard() *cobra.Command {
 
 	return cmd
 }
+
+// getGrafanaAddr returns the address of the Grafana instance the web
+// deployment proxies /grafana to, which is an external instance when
+// grafanaUrl is set, or empty if Grafana is disabled altogether
+func getGrafanaAddr(web *appsv1.Deployment) string {
+	for _, c := range web.Spec.Template.Spec.Containers {
+		for _, arg := range c.Args {
+			if strings.HasPrefix(arg, grafanaAddrArg) {
+				return strings.TrimPrefix(arg, grafanaAddrArg)
+			}
+		}
+	}
+	return ""
+}
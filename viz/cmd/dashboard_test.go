@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestGetGrafanaAddr(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{
+			[]string{"-api-addr=metrics-api.linkerd-viz.svc.cluster.local:8085", "-grafana-addr=grafana.linkerd-viz.svc.cluster.local:3000"},
+			"grafana.linkerd-viz.svc.cluster.local:3000",
+		},
+		{
+			[]string{"-api-addr=metrics-api.linkerd-viz.svc.cluster.local:8085", "-grafana-addr=external-grafana.com"},
+			"external-grafana.com",
+		},
+		{
+			[]string{"-api-addr=metrics-api.linkerd-viz.svc.cluster.local:8085"},
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.expected, func(t *testing.T) {
+			web := &appsv1.Deployment{}
+			web.Spec.Template.Spec.Containers = []corev1.Container{{Name: "web", Args: tc.args}}
+			if addr := getGrafanaAddr(web); addr != tc.expected {
+				t.Fatalf("Expected grafana address %q, got %q", tc.expected, addr)
+			}
+		})
+	}
+}
@@ -134,12 +134,17 @@ func NewCmdEdges() *cobra.Command {
 				ImpersonateGroup:      impersonateGroup,
 				KubeContext:           kubeContext,
 				APIAddr:               apiAddr,
+				APITimeout:            apiTimeout,
+				TraceAPIRequests:      traceCollector != "",
 			})
 
+			ctx, cancel := newRequestContext(cmd.Context())
+			defer cancel()
+
 			c := make(chan indexedEdgeResults, len(reqs))
 			for num, req := range reqs {
 				go func(num int, req *pb.EdgesRequest) {
-					resp, err := requestEdgesFromAPI(client, req)
+					resp, err := requestEdgesFromAPI(ctx, client, req)
 					rows := edgesRespToRows(resp)
 					c <- indexedEdgeResults{num, rows, err}
 				}(num, req)
@@ -232,8 +237,8 @@ func edgesRespToRows(resp *pb.EdgesResponse) []*pb.Edge {
 	return rows
 }
 
-func requestEdgesFromAPI(client pb.ApiClient, req *pb.EdgesRequest) (*pb.EdgesResponse, error) {
-	resp, err := client.Edges(context.Background(), req)
+func requestEdgesFromAPI(ctx context.Context, client pb.ApiClient, req *pb.EdgesRequest) (*pb.EdgesResponse, error) {
+	resp, err := client.Edges(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("Edges API error: %+v", err)
 	}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"testing"
 
 	api "github.com/linkerd/linkerd2/viz/metrics-api"
@@ -110,7 +111,7 @@ func testEdgesCall(exp edgesParamsExp, t *testing.T) {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 
-	resp, err := requestEdgesFromAPI(mockClient, reqs[0])
+	resp, err := requestEdgesFromAPI(context.Background(), mockClient, reqs[0])
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
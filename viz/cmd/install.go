@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"path"
+	"regexp"
 	"time"
 
 	"github.com/linkerd/linkerd2/pkg/charts"
@@ -13,6 +15,7 @@ import (
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
 	api "github.com/linkerd/linkerd2/pkg/public"
 	"github.com/linkerd/linkerd2/viz/static"
+	"github.com/prometheus/common/model"
 	"github.com/spf13/cobra"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/chartutil"
@@ -20,7 +23,14 @@ import (
 	"helm.sh/helm/v3/pkg/engine"
 )
 
+const (
+	retentionTimeArg = "storage.tsdb.retention.time"
+	retentionSizeArg = "storage.tsdb.retention.size"
+)
+
 var (
+	retentionSizeRegex = regexp.MustCompile(`^[0-9]+(B|KB|MB|GB|TB|PB|EB)$`)
+
 	templatesViz = []string{
 		"templates/namespace.yaml",
 		"templates/metrics-api-rbac.yaml",
@@ -105,11 +115,39 @@ func install(w io.Writer, options values.Options, ha bool) error {
 		}
 	}
 
-	// TODO: Add any validation logic here
+	if err := validateValues(valuesOverrides); err != nil {
+		return err
+	}
 
 	return render(w, valuesOverrides)
 }
 
+// validateValues checks the Prometheus retention args, if overridden, parse
+// the way Prometheus itself expects them
+func validateValues(valuesOverrides map[string]interface{}) error {
+	prom, ok := valuesOverrides["prometheus"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	args, ok := prom["args"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	if retention, ok := args[retentionTimeArg]; ok {
+		if _, err := model.ParseDuration(fmt.Sprint(retention)); err != nil {
+			return fmt.Errorf("invalid prometheus %s '%v': %s", retentionTimeArg, retention, err)
+		}
+	}
+	if retention, ok := args[retentionSizeArg]; ok {
+		if !retentionSizeRegex.MatchString(fmt.Sprint(retention)) {
+			return fmt.Errorf("invalid prometheus %s '%v': must be a size such as 512MB", retentionSizeArg, retention)
+		}
+	}
+
+	return nil
+}
+
 func render(w io.Writer, valuesOverrides map[string]interface{}) error {
 
 	files := []*loader.BufferedFile{
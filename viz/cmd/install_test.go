@@ -109,3 +109,42 @@ func TestRender(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateValues(t *testing.T) {
+	testCases := []struct {
+		args map[string]interface{}
+		err  string
+	}{
+		{nil, ""},
+		{map[string]interface{}{"storage.tsdb.retention.time": "15d"}, ""},
+		{map[string]interface{}{"storage.tsdb.retention.time": "1y"}, ""},
+		{map[string]interface{}{"storage.tsdb.retention.size": "512MB"}, ""},
+		{
+			map[string]interface{}{"storage.tsdb.retention.time": "6 hours"},
+			"invalid prometheus storage.tsdb.retention.time '6 hours': not a valid duration string: \"6 hours\"",
+		},
+		{
+			map[string]interface{}{"storage.tsdb.retention.size": "1G"},
+			"invalid prometheus storage.tsdb.retention.size '1G': must be a size such as 512MB",
+		},
+	}
+
+	for i, tc := range testCases {
+		tc := tc // pin
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			values := map[string]interface{}{
+				"prometheus": map[string]interface{}{"args": tc.args},
+			}
+			err := validateValues(values)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.err {
+				t.Fatalf("Expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
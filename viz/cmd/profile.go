@@ -8,12 +8,15 @@ import (
 	"io"
 	"net"
 	"os"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ghodss/yaml"
 	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha2"
+	spclient "github.com/linkerd/linkerd2/controller/gen/client/clientset/versioned"
 	pkgcmd "github.com/linkerd/linkerd2/pkg/cmd"
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
 	"github.com/linkerd/linkerd2/pkg/k8s"
@@ -24,6 +27,7 @@ import (
 	"github.com/linkerd/linkerd2/viz/tap/pkg"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 )
@@ -34,13 +38,75 @@ type profileOptions struct {
 	tap           string
 	tapDuration   time.Duration
 	tapRouteLimit uint
+	tapMaxRps     float32
+	tapCollapseID bool
+	merge         bool
+
+	defaultTimeout     time.Duration
+	defaultRetryable   bool
+	defaultRetryBudget string
+}
+
+// profileDefaults holds the reliability settings applied to generated
+// profiles
+type profileDefaults struct {
+	timeout     time.Duration
+	retryable   bool
+	retryBudget *sp.RetryBudget
 }
 
+// idPathSegmentRegex matches path segments that look like resource IDs, i.e.
+// integers and UUIDs
+var idPathSegmentRegex = regexp.MustCompile(`^([0-9]+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`)
+
 func newProfileOptions() *profileOptions {
 	return &profileOptions{
 		tapDuration:   5 * time.Second,
 		tapRouteLimit: 20,
+		tapMaxRps:     maxRps,
+		tapCollapseID: false,
+		merge:         false,
+	}
+}
+
+// parseRetryBudget parses a retry budget of the form
+// "retryRatio,minRetriesPerSecond,ttl", e.g. "0.2,10,10s"
+func parseRetryBudget(budget string) (*sp.RetryBudget, error) {
+	parts := strings.Split(budget, ",")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid retry budget %q: expected retryRatio,minRetriesPerSecond,ttl", budget)
+	}
+	ratio, err := strconv.ParseFloat(parts[0], 32)
+	if err != nil || ratio < 0 {
+		return nil, fmt.Errorf("invalid retry budget %q: retryRatio must be a non-negative number", budget)
+	}
+	minRetries, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid retry budget %q: minRetriesPerSecond must be a non-negative integer", budget)
+	}
+	if _, err := time.ParseDuration(parts[2]); err != nil {
+		return nil, fmt.Errorf("invalid retry budget %q: %s", budget, err)
+	}
+	return &sp.RetryBudget{
+		RetryRatio:          float32(ratio),
+		MinRetriesPerSecond: uint32(minRetries),
+		TTL:                 parts[2],
+	}, nil
+}
+
+func (options *profileOptions) defaults() (profileDefaults, error) {
+	defaults := profileDefaults{
+		timeout:   options.defaultTimeout,
+		retryable: options.defaultRetryable,
+	}
+	if options.defaultRetryBudget != "" {
+		budget, err := parseRetryBudget(options.defaultRetryBudget)
+		if err != nil {
+			return defaults, err
+		}
+		defaults.retryBudget = budget
 	}
+	return defaults, nil
 }
 
 func (options *profileOptions) validate() error {
@@ -57,7 +123,14 @@ func (options *profileOptions) validate() error {
 	if errs := validation.IsDNS1123Label(options.namespace); len(errs) != 0 {
 		return fmt.Errorf("invalid namespace %q: %v", options.namespace, errs)
 	}
-	return nil
+	if options.tapMaxRps <= 0 {
+		return errors.New("The --tap-max-rps flag must be positive")
+	}
+	if options.defaultTimeout < 0 {
+		return errors.New("The --default-timeout flag must not be negative")
+	}
+	_, err := options.defaults()
+	return err
 }
 
 // newCmdProfile creates a new cobra command for the Profile subcommand which
@@ -71,6 +144,18 @@ func newCmdProfile() *cobra.Command {
 		Long:  "Output service profile config for Kubernetes based off tap data.",
 		Example: `  # Generate a profile by watching live traffic.
   linkerd viz profile -n emojivoto web-svc --tap deploy/web --tap-duration 10s --tap-route-limit 5
+
+  # Generate a profile from live traffic, merging paths such as /books/1 and
+  # /books/2 into a single "/books/{id}" route.
+  linkerd viz profile -n booksapp books --tap deploy/books --tap-collapse-ids
+
+  # Add newly observed routes to the existing web-svc profile, keeping the
+  # existing routes and settings such as its retry budget.
+  linkerd viz profile -n emojivoto web-svc --tap deploy/web --merge
+
+  # Generate a profile whose routes are retryable and time out after 300ms,
+  # retrying at most 20% of requests on top of 10 retries per second.
+  linkerd viz profile -n emojivoto web-svc --tap deploy/web --default-timeout 300ms --default-retryable --default-retry-budget 0.2,10,10s
 `,
 		Args: cobra.ExactArgs(1),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -109,6 +194,8 @@ func newCmdProfile() *cobra.Command {
 				ImpersonateGroup:      impersonateGroup,
 				KubeContext:           kubeContext,
 				APIAddr:               apiAddr,
+				APITimeout:            apiTimeout,
+				TraceAPIRequests:      traceCollector != "",
 			})
 			if options.namespace == "" {
 				options.namespace = pkgcmd.GetDefaultNamespace(kubeconfigPath, kubeContext)
@@ -131,12 +218,29 @@ func newCmdProfile() *cobra.Command {
 			if cd := values.ClusterDomain; cd != "" {
 				clusterDomain = cd
 			}
-			return renderTapOutputProfile(cmd.Context(), k8sAPI, options.tap, options.namespace, options.name, clusterDomain, options.tapDuration, int(options.tapRouteLimit), os.Stdout)
+			defaults, err := options.defaults()
+			if err != nil {
+				return err
+			}
+			var existing *sp.ServiceProfile
+			if options.merge {
+				existing, err = getServiceProfile(cmd.Context(), k8sAPI, options.namespace, profileName(options.namespace, options.name, clusterDomain))
+				if err != nil {
+					return err
+				}
+			}
+			return renderTapOutputProfile(cmd.Context(), k8sAPI, options.tap, options.namespace, options.name, clusterDomain, options.tapDuration, options.tapMaxRps, int(options.tapRouteLimit), options.tapCollapseID, defaults, existing, os.Stdout)
 		},
 	}
 	cmd.PersistentFlags().StringVar(&options.tap, "tap", options.tap, "Output a service profile based on tap data for the given target resource")
 	cmd.PersistentFlags().DurationVar(&options.tapDuration, "tap-duration", options.tapDuration, "Duration over which tap data is collected (for example: \"10s\", \"1m\", \"10m\")")
 	cmd.PersistentFlags().UintVar(&options.tapRouteLimit, "tap-route-limit", options.tapRouteLimit, "Max number of routes to add to the profile")
+	cmd.PersistentFlags().Float32Var(&options.tapMaxRps, "tap-max-rps", options.tapMaxRps, "Maximum requests per second to tap while generating the profile")
+	cmd.PersistentFlags().BoolVar(&options.merge, "merge", options.merge, "Merge the routes observed with tap into the service's existing profile, if any, preserving its routes and settings")
+	cmd.PersistentFlags().BoolVar(&options.tapCollapseID, "tap-collapse-ids", options.tapCollapseID, "Collapse numeric and UUID path segments into a shared \"{id}\" route template")
+	cmd.PersistentFlags().DurationVar(&options.defaultTimeout, "default-timeout", options.defaultTimeout, "Timeout set on every generated route (for example: \"300ms\", \"1s\"); no timeout is set if zero")
+	cmd.PersistentFlags().BoolVar(&options.defaultRetryable, "default-retryable", options.defaultRetryable, "Mark every generated route as retryable")
+	cmd.PersistentFlags().StringVar(&options.defaultRetryBudget, "default-retry-budget", options.defaultRetryBudget, "Retry budget set on the generated profile, as \"retryRatio,minRetriesPerSecond,ttl\" (for example: \"0.2,10,10s\")")
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the service")
 
 	pkgcmd.ConfigureNamespaceFlagCompletion(
@@ -147,21 +251,29 @@ func newCmdProfile() *cobra.Command {
 
 // renderTapOutputProfile performs a tap on the desired resource and generates
 // a service profile with routes pre-populated from the tap data
-// Only inbound tap traffic is considered.
-func renderTapOutputProfile(ctx context.Context, k8sAPI *k8s.KubernetesAPI, tapResource, namespace, name, clusterDomain string, tapDuration time.Duration, routeLimit int, w io.Writer) error {
+// Only inbound tap traffic is considered, and routes are sorted by path regex
+// and method. The defaults are applied to the
+// generated profile and, if existing is not nil, the tap routes are merged
+// into it.
+func renderTapOutputProfile(ctx context.Context, k8sAPI *k8s.KubernetesAPI, tapResource, namespace, name, clusterDomain string, tapDuration time.Duration, maxRps float32, routeLimit int, collapseIDs bool, defaults profileDefaults, existing *sp.ServiceProfile, w io.Writer) error {
 	requestParams := pkg.TapRequestParams{
 		Resource:  tapResource,
 		Namespace: namespace,
+		MaxRps:    maxRps,
 	}
 	log.Debugf("Running `linkerd tap %s --namespace %s`", tapResource, namespace)
 	req, err := pkg.BuildTapByResourceRequest(requestParams)
 	if err != nil {
 		return err
 	}
-	profile, err := tapToServiceProfile(ctx, k8sAPI, req, namespace, name, clusterDomain, tapDuration, routeLimit)
+	profile, err := tapToServiceProfile(ctx, k8sAPI, req, namespace, name, clusterDomain, tapDuration, routeLimit, collapseIDs)
 	if err != nil {
 		return err
 	}
+	applyProfileDefaults(&profile, defaults)
+	if existing != nil {
+		profile = mergeServiceProfiles(*existing, profile)
+	}
 	output, err := yaml.Marshal(profile)
 	if err != nil {
 		return fmt.Errorf("Error writing Service Profile: %s", err)
@@ -170,10 +282,10 @@ func renderTapOutputProfile(ctx context.Context, k8sAPI *k8s.KubernetesAPI, tapR
 	return nil
 }
 
-func tapToServiceProfile(ctx context.Context, k8sAPI *k8s.KubernetesAPI, tapReq *pb.TapByResourceRequest, namespace, name, clusterDomain string, tapDuration time.Duration, routeLimit int) (sp.ServiceProfile, error) {
+func tapToServiceProfile(ctx context.Context, k8sAPI *k8s.KubernetesAPI, tapReq *pb.TapByResourceRequest, namespace, name, clusterDomain string, tapDuration time.Duration, routeLimit int, collapseIDs bool) (sp.ServiceProfile, error) {
 	profile := sp.ServiceProfile{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s.%s.svc.%s", name, namespace, clusterDomain),
+			Name:      profileName(namespace, name, clusterDomain),
 			Namespace: namespace,
 		},
 		TypeMeta: profiles.ServiceProfileMeta,
@@ -185,12 +297,75 @@ func tapToServiceProfile(ctx context.Context, k8sAPI *k8s.KubernetesAPI, tapReq
 		return profile, err
 	}
 	defer body.Close()
-	routes := routeSpecFromTap(reader, routeLimit)
+	routes := routeSpecFromTap(reader, routeLimit, collapseIDs)
 	profile.Spec.Routes = routes
 	return profile, nil
 }
 
-func routeSpecFromTap(tapByteStream *bufio.Reader, routeLimit int) []*sp.RouteSpec {
+// applyProfileDefaults sets the default timeout, retryability and retry
+// budget on the given profile
+func applyProfileDefaults(profile *sp.ServiceProfile, defaults profileDefaults) {
+	for _, route := range profile.Spec.Routes {
+		if defaults.timeout > 0 {
+			route.Timeout = defaults.timeout.String()
+		}
+		if defaults.retryable {
+			route.IsRetryable = true
+		}
+	}
+	if defaults.retryBudget != nil {
+		profile.Spec.RetryBudget = defaults.retryBudget
+	}
+}
+
+// profileName returns the name of the ServiceProfile for the given service
+func profileName(namespace, name, clusterDomain string) string {
+	return fmt.Sprintf("%s.%s.svc.%s", name, namespace, clusterDomain)
+}
+
+// getServiceProfile returns the ServiceProfile with the given name, or nil if
+// it doesn't exist
+func getServiceProfile(ctx context.Context, k8sAPI *k8s.KubernetesAPI, namespace, name string) (*sp.ServiceProfile, error) {
+	client, err := spclient.NewForConfig(k8sAPI.Config)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := client.LinkerdV1alpha2().ServiceProfiles(namespace).Get(ctx, name, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		log.Debugf("ServiceProfile %s/%s not found, nothing to merge", namespace, name)
+		return nil, nil
+	}
+	return profile, err
+}
+
+// mergeServiceProfiles returns the existing profile with the routes of
+// generated that it doesn't already have appended to it. All the other
+// fields of existing, including its routes, are preserved.
+func mergeServiceProfiles(existing, generated sp.ServiceProfile) sp.ServiceProfile {
+	merged := sp.ServiceProfile{
+		TypeMeta: profiles.ServiceProfileMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        existing.Name,
+			Namespace:   existing.Namespace,
+			Labels:      existing.Labels,
+			Annotations: existing.Annotations,
+		},
+		Spec: *existing.Spec.DeepCopy(),
+	}
+
+	known := make(map[string]struct{})
+	for _, route := range merged.Spec.Routes {
+		known[route.Name] = struct{}{}
+	}
+	for _, route := range generated.Spec.Routes {
+		if _, ok := known[route.Name]; !ok {
+			merged.Spec.Routes = append(merged.Spec.Routes, route)
+		}
+	}
+	return merged
+}
+
+func routeSpecFromTap(tapByteStream *bufio.Reader, routeLimit int, collapseIDs bool) []*sp.RouteSpec {
 	routes := make([]*sp.RouteSpec, 0)
 	routesMap := make(map[string]*sp.RouteSpec)
 	for {
@@ -208,7 +383,7 @@ func routeSpecFromTap(tapByteStream *bufio.Reader, routeLimit int) []*sp.RouteSp
 			}
 			break
 		}
-		routeSpec := getPathDataFromTap(&event)
+		routeSpec := getPathDataFromTap(&event, collapseIDs)
 		log.Debugf("Created route spec: %v", routeSpec)
 		if routeSpec != nil {
 			routesMap[routeSpec.Name] = routeSpec
@@ -217,21 +392,29 @@ func routeSpecFromTap(tapByteStream *bufio.Reader, routeLimit int) []*sp.RouteSp
 			}
 		}
 	}
-	for _, path := range sortMapKeys(routesMap) {
-		routes = append(routes, routesMap[path])
+	for _, route := range routesMap {
+		routes = append(routes, route)
 	}
+	sortRoutes(routes)
 	return routes
 }
 
-func sortMapKeys(m map[string]*sp.RouteSpec) (keys []string) {
-	for key := range m {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-	return
+// sortRoutes sorts routes by path regex and then by method, so that
+// regenerating a profile from the same traffic always yields the same output
+func sortRoutes(routes []*sp.RouteSpec) {
+	sort.Slice(routes, func(i, j int) bool {
+		ci, cj := routes[i].Condition, routes[j].Condition
+		if ci.PathRegex != cj.PathRegex {
+			return ci.PathRegex < cj.PathRegex
+		}
+		if ci.Method != cj.Method {
+			return ci.Method < cj.Method
+		}
+		return routes[i].Name < routes[j].Name
+	})
 }
 
-func getPathDataFromTap(event *pb.TapEvent) *sp.RouteSpec {
+func getPathDataFromTap(event *pb.TapEvent, collapseIDs bool) *sp.RouteSpec {
 	if event.GetProxyDirection() != pb.TapEvent_INBOUND {
 		return nil
 	}
@@ -242,12 +425,29 @@ func getPathDataFromTap(event *pb.TapEvent) *sp.RouteSpec {
 			return nil
 		}
 
+		if collapseIDs {
+			path = collapseIDPathSegments(path)
+		}
+
 		return profiles.MkRouteSpec(
 			path,
-			profiles.PathToRegex(path), // for now, no path consolidation
+			profiles.PathToRegex(path),
 			ev.RequestInit.GetMethod().GetRegistered().String(),
 			nil)
 	default:
 		return nil
 	}
 }
+
+// collapseIDPathSegments replaces the segments of path that look like IDs
+// with an "{id}" template, so that e.g. /users/123 and /users/456 both
+// become /users/{id} and end up in the same route
+func collapseIDPathSegments(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if idPathSegmentRegex.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
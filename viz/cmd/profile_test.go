@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	sp "github.com/linkerd/linkerd2/controller/gen/apis/serviceprofile/v1alpha2"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/profiles"
@@ -105,24 +108,24 @@ func TestTapToServiceProfile(t *testing.T) {
 		Spec: sp.ServiceProfileSpec{
 			Routes: []*sp.RouteSpec{
 				{
-					Name: "GET /my/path/hi",
+					Name: "POST /emojivoto.v1.VotingService/VoteFire",
 					Condition: &sp.RequestMatch{
-						PathRegex: `/my/path/hi`,
-						Method:    "GET",
+						PathRegex: `/emojivoto\.v1\.VotingService/VoteFire`,
+						Method:    "POST",
 					},
 				},
 				{
-					Name: "POST /emojivoto.v1.VotingService/VoteFire",
+					Name: "GET /my/path/hi",
 					Condition: &sp.RequestMatch{
-						PathRegex: `/emojivoto\.v1\.VotingService/VoteFire`,
-						Method:    "POST",
+						PathRegex: `/my/path/hi`,
+						Method:    "GET",
 					},
 				},
 			},
 		},
 	}
 
-	actualServiceProfile, err := tapToServiceProfile(context.Background(), kubeAPI, tapReq, namespace, name, clusterDomain, tapDuration, routeLimit)
+	actualServiceProfile, err := tapToServiceProfile(context.Background(), kubeAPI, tapReq, namespace, name, clusterDomain, tapDuration, routeLimit, false)
 	if err != nil {
 		t.Fatalf("Failed to create ServiceProfile: %v", err)
 	}
@@ -132,3 +135,213 @@ func TestTapToServiceProfile(t *testing.T) {
 		t.Fatalf("ServiceProfiles are not equal: %v", err)
 	}
 }
+
+func TestCollapseIDPathSegments(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{"/users/123", "/users/{id}"},
+		{"/users/123/books/456", "/users/{id}/books/{id}"},
+		{"/users/3f2504e0-4f89-11d3-9a0c-0305e82c3301", "/users/{id}"},
+		{"/users/abc", "/users/abc"},
+		{"/v1/users", "/v1/users"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.path, func(t *testing.T) {
+			actual := collapseIDPathSegments(tc.path)
+			if actual != tc.expected {
+				t.Fatalf("Expected %s, got %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMergeServiceProfiles(t *testing.T) {
+	retryBudget := &sp.RetryBudget{
+		RetryRatio:          0.2,
+		MinRetriesPerSecond: 10,
+		TTL:                 "10s",
+	}
+	existing := sp.ServiceProfile{
+		TypeMeta: profiles.ServiceProfileMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-svc.emojivoto.svc.cluster.local",
+			Namespace:       "emojivoto",
+			ResourceVersion: "1234",
+		},
+		Spec: sp.ServiceProfileSpec{
+			Routes: []*sp.RouteSpec{
+				{
+					Name:        "GET /api/list",
+					Condition:   &sp.RequestMatch{PathRegex: "/api/list", Method: "GET"},
+					IsRetryable: true,
+				},
+			},
+			RetryBudget: retryBudget,
+		},
+	}
+	generated := sp.ServiceProfile{
+		TypeMeta: profiles.ServiceProfileMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-svc.emojivoto.svc.cluster.local",
+			Namespace: "emojivoto",
+		},
+		Spec: sp.ServiceProfileSpec{
+			Routes: []*sp.RouteSpec{
+				{
+					Name:      "GET /api/list",
+					Condition: &sp.RequestMatch{PathRegex: "/api/list", Method: "GET"},
+				},
+				{
+					Name:      "POST /api/vote",
+					Condition: &sp.RequestMatch{PathRegex: "/api/vote", Method: "POST"},
+				},
+			},
+		},
+	}
+
+	expected := sp.ServiceProfile{
+		TypeMeta: profiles.ServiceProfileMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web-svc.emojivoto.svc.cluster.local",
+			Namespace: "emojivoto",
+		},
+		Spec: sp.ServiceProfileSpec{
+			Routes: []*sp.RouteSpec{
+				{
+					Name:        "GET /api/list",
+					Condition:   &sp.RequestMatch{PathRegex: "/api/list", Method: "GET"},
+					IsRetryable: true,
+				},
+				{
+					Name:      "POST /api/vote",
+					Condition: &sp.RequestMatch{PathRegex: "/api/vote", Method: "POST"},
+				},
+			},
+			RetryBudget: retryBudget,
+		},
+	}
+
+	actual := mergeServiceProfiles(existing, generated)
+	if err := profiles.ServiceProfileYamlEquals(actual, expected); err != nil {
+		t.Fatalf("ServiceProfiles are not equal: %v", err)
+	}
+}
+
+func TestParseRetryBudget(t *testing.T) {
+	testCases := []struct {
+		budget   string
+		expected *sp.RetryBudget
+		err      string
+	}{
+		{"0.2,10,10s", &sp.RetryBudget{RetryRatio: 0.2, MinRetriesPerSecond: 10, TTL: "10s"}, ""},
+		{"0.2,10", nil, "invalid retry budget \"0.2,10\": expected retryRatio,minRetriesPerSecond,ttl"},
+		{"-1,10,10s", nil, "invalid retry budget \"-1,10,10s\": retryRatio must be a non-negative number"},
+		{"0.2,-10,10s", nil, "invalid retry budget \"0.2,-10,10s\": minRetriesPerSecond must be a non-negative integer"},
+		{"0.2,10,ten", nil, "invalid retry budget \"0.2,10,ten\": time: invalid duration \"ten\""},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(tc.budget, func(t *testing.T) {
+			budget, err := parseRetryBudget(tc.budget)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("Expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if *budget != *tc.expected {
+				t.Fatalf("Expected %+v, got %+v", tc.expected, budget)
+			}
+		})
+	}
+}
+
+func TestApplyProfileDefaults(t *testing.T) {
+	retryBudget := &sp.RetryBudget{RetryRatio: 0.2, MinRetriesPerSecond: 10, TTL: "10s"}
+	profile := sp.ServiceProfile{
+		Spec: sp.ServiceProfileSpec{
+			Routes: []*sp.RouteSpec{
+				{Name: "GET /api/list", Condition: &sp.RequestMatch{PathRegex: "/api/list", Method: "GET"}},
+				{Name: "POST /api/vote", Condition: &sp.RequestMatch{PathRegex: "/api/vote", Method: "POST"}},
+			},
+		},
+	}
+
+	applyProfileDefaults(&profile, profileDefaults{
+		timeout:     300 * time.Millisecond,
+		retryable:   true,
+		retryBudget: retryBudget,
+	})
+
+	for _, route := range profile.Spec.Routes {
+		if route.Timeout != "300ms" {
+			t.Fatalf("Expected route %s to have timeout 300ms, got %q", route.Name, route.Timeout)
+		}
+		if !route.IsRetryable {
+			t.Fatalf("Expected route %s to be retryable", route.Name)
+		}
+	}
+	if profile.Spec.RetryBudget != retryBudget {
+		t.Fatalf("Expected retry budget %+v, got %+v", retryBudget, profile.Spec.RetryBudget)
+	}
+}
+
+func mkRequestInitEvent(path string, method metricsPb.HttpMethod_Registered) *tapPb.TapEvent {
+	return pkg.CreateTapEvent(
+		&tapPb.TapEvent_Http{
+			Event: &tapPb.TapEvent_Http_RequestInit_{
+				RequestInit: &tapPb.TapEvent_Http_RequestInit{
+					Path: path,
+					Method: &metricsPb.HttpMethod{
+						Type: &metricsPb.HttpMethod_Registered_{
+							Registered: method,
+						},
+					},
+				},
+			},
+		},
+		map[string]string{},
+		tapPb.TapEvent_INBOUND,
+	)
+}
+
+func TestRouteSpecFromTapIsSorted(t *testing.T) {
+	events := []*tapPb.TapEvent{
+		mkRequestInitEvent("/books", metricsPb.HttpMethod_POST),
+		mkRequestInitEvent("/authors", metricsPb.HttpMethod_GET),
+		mkRequestInitEvent("/books", metricsPb.HttpMethod_GET),
+		mkRequestInitEvent("/authors/new", metricsPb.HttpMethod_POST),
+	}
+	expectedNames := []string{"GET /authors", "POST /authors/new", "GET /books", "POST /books"}
+
+	// feed the same events in every rotation; the routes must come out in
+	// the same order each time
+	for i := range events {
+		var buf bytes.Buffer
+		for j := range events {
+			msg, err := proto.Marshal(events[(i+j)%len(events)])
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			buf.Write(protohttp.SerializeAsPayload(msg))
+		}
+
+		routes := routeSpecFromTap(bufio.NewReader(&buf), 20, false)
+		if len(routes) != len(expectedNames) {
+			t.Fatalf("Expected %d routes, got %d", len(expectedNames), len(routes))
+		}
+		for k, route := range routes {
+			if route.Name != expectedNames[k] {
+				t.Fatalf("Rotation %d: expected route %d to be %s, got %s", i, k, expectedNames[k], route.Name)
+			}
+		}
+	}
+}
@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"regexp"
+	"time"
 
 	"github.com/fatih/color"
 	pkgcmd "github.com/linkerd/linkerd2/pkg/cmd"
 	"github.com/linkerd/linkerd2/pkg/healthcheck"
+	"github.com/linkerd/linkerd2/pkg/trace"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -22,6 +27,8 @@ const (
 	vizChartName            = "linkerd-viz"
 	defaultLinkerdNamespace = "linkerd"
 	maxRps                  = 100.0
+	defaultAPITimeout       = 30 * time.Second
+	traceServiceName        = "linkerd-viz-cli"
 
 	jsonOutput  = healthcheck.JSONOutput
 	tableOutput = healthcheck.TableOutput
@@ -35,11 +42,13 @@ var (
 	stderr = color.Error
 
 	apiAddr               string // An empty value means "use the Kubernetes configuration"
+	apiTimeout            time.Duration
 	controlPlaneNamespace string
 	kubeconfigPath        string
 	kubeContext           string
 	impersonate           string
 	impersonateGroup      []string
+	traceCollector        string
 	verbose               bool
 
 	// These regexs are not as strict as they could be, but are a quick and dirty
@@ -65,8 +74,20 @@ func NewCmdViz() *cobra.Command {
 				return fmt.Errorf("%s is not a valid namespace", controlPlaneNamespace)
 			}
 
+			if err := pkgcmd.ValidateImpersonation(impersonate, impersonateGroup); err != nil {
+				return err
+			}
+
+			if traceCollector != "" {
+				if err := trace.InitializeTracing(traceServiceName, traceCollector); err != nil {
+					return fmt.Errorf("failed to initialize tracing: %w", err)
+				}
+			}
 			return nil
 		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			trace.Flush()
+		},
 	}
 
 	vizCmd.PersistentFlags().StringVarP(&controlPlaneNamespace, "linkerd-namespace", "L", defaultLinkerdNamespace, "Namespace in which Linkerd is installed")
@@ -75,6 +96,8 @@ func NewCmdViz() *cobra.Command {
 	vizCmd.PersistentFlags().StringVar(&impersonate, "as", "", "Username to impersonate for Kubernetes operations")
 	vizCmd.PersistentFlags().StringArrayVar(&impersonateGroup, "as-group", []string{}, "Group to impersonate for Kubernetes operations")
 	vizCmd.PersistentFlags().StringVar(&apiAddr, "api-addr", "", "Override kubeconfig and communicate directly with the control plane at host:port (mostly for testing)")
+	vizCmd.PersistentFlags().DurationVar(&apiTimeout, "api-timeout", defaultAPITimeout, "Time allowed for each request to the viz API (0 means no timeout)")
+	vizCmd.PersistentFlags().StringVar(&traceCollector, "trace-collector", "", "Address of an OpenCensus collector to which traces of the requests made to the viz API are sent; requests aren't traced if unset")
 	vizCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Turn on debug logging")
 	vizCmd.AddCommand(NewCmdCheck())
 	vizCmd.AddCommand(NewCmdDashboard())
@@ -96,3 +119,18 @@ func NewCmdViz() *cobra.Command {
 	pkgcmd.ConfigureKubeContextFlagCompletion(vizCmd, kubeconfigPath)
 	return vizCmd
 }
+
+// newRequestContext returns a context for requests to the viz API, canceled
+// on interrupt and, unless --api-timeout is zero, once it elapses
+func newRequestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt)
+	if apiTimeout == 0 {
+		return ctx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, apiTimeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
@@ -75,18 +75,21 @@ This command will only display traffic which is sent to a service that has a Ser
 				return fmt.Errorf("error creating metrics request while making routes request: %v", err)
 			}
 
-			output, err := requestRouteStatsFromAPI(
-				api.CheckClientOrExit(healthcheck.Options{
-					ControlPlaneNamespace: controlPlaneNamespace,
-					KubeConfig:            kubeconfigPath,
-					Impersonate:           impersonate,
-					ImpersonateGroup:      impersonateGroup,
-					KubeContext:           kubeContext,
-					APIAddr:               apiAddr,
-				}),
-				req,
-				options,
-			)
+			client := api.CheckClientOrExit(healthcheck.Options{
+				ControlPlaneNamespace: controlPlaneNamespace,
+				KubeConfig:            kubeconfigPath,
+				Impersonate:           impersonate,
+				ImpersonateGroup:      impersonateGroup,
+				KubeContext:           kubeContext,
+				APIAddr:               apiAddr,
+				APITimeout:            apiTimeout,
+				TraceAPIRequests:      traceCollector != "",
+			})
+
+			ctx, cancel := newRequestContext(cmd.Context())
+			defer cancel()
+
+			output, err := requestRouteStatsFromAPI(ctx, client, req, options)
 			if err != nil {
 				fmt.Fprint(os.Stderr, err.Error())
 				os.Exit(1)
@@ -111,8 +114,8 @@ This command will only display traffic which is sent to a service that has a Ser
 	return cmd
 }
 
-func requestRouteStatsFromAPI(client pb.ApiClient, req *pb.TopRoutesRequest, options *routesOptions) (string, error) {
-	resp, err := client.TopRoutes(context.Background(), req)
+func requestRouteStatsFromAPI(ctx context.Context, client pb.ApiClient, req *pb.TopRoutesRequest, options *routesOptions) (string, error) {
+	resp, err := client.TopRoutes(ctx, req)
 	if err != nil {
 		return "", fmt.Errorf("TopRoutes API error: %v", err)
 	}
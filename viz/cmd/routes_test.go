@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"testing"
 
 	api "github.com/linkerd/linkerd2/viz/metrics-api"
@@ -59,7 +60,7 @@ func testRoutesCall(exp routesParamsExp, t *testing.T) {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 
-	output, err := requestRouteStatsFromAPI(mockClient, req, exp.options)
+	output, err := requestRouteStatsFromAPI(context.Background(), mockClient, req, exp.options)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
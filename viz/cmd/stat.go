@@ -33,6 +33,9 @@ type statOptions struct {
 	allNamespaces bool
 	labelSelector string
 	unmeshed      bool
+	direction     string
+	totals        bool
+	compareWindow string
 }
 
 type statOptionsBase struct {
@@ -57,6 +60,11 @@ func (o *statOptionsBase) validateOutputFormat() error {
 	}
 }
 
+const (
+	inboundDirection  = "inbound"
+	outboundDirection = "outbound"
+)
+
 type indexedResults struct {
 	ix   int
 	rows []*pb.StatTable_PodGroup_Row
@@ -73,6 +81,9 @@ func newStatOptions() *statOptions {
 		allNamespaces:   false,
 		labelSelector:   "",
 		unmeshed:        false,
+		direction:       inboundDirection,
+		totals:          false,
+		compareWindow:   "",
 	}
 }
 
@@ -166,7 +177,13 @@ If no resource name is specified, displays stats about all resources of the spec
   linkerd viz stat namespaces --from ns/default
 
   # Get all inbound stats to the test namespace.
-  linkerd viz stat ns/test`,
+  linkerd viz stat ns/test
+
+  # Get all outbound stats from the web deployment.
+  linkerd viz stat deploy/web --direction outbound
+
+  # Compare the stats of the last minute with those of the last hour for all deployments in the test namespace.
+  linkerd viz stat deploy -n test --time-window 1m --compare-window 1h`,
 		Args: cobra.MinimumNArgs(1),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 
@@ -197,7 +214,7 @@ If no resource name is specified, displays stats about all resources of the spec
 				options.namespace = pkgcmd.GetDefaultNamespace(kubeconfigPath, kubeContext)
 			}
 
-			reqs, err := buildStatSummaryRequests(args, options)
+			reqs, numReqs, err := buildStatRequests(args, options)
 			if err != nil {
 				return fmt.Errorf("error creating metrics request while making stats request: %v", err)
 			}
@@ -211,31 +228,41 @@ If no resource name is specified, displays stats about all resources of the spec
 				ImpersonateGroup:      impersonateGroup,
 				KubeContext:           kubeContext,
 				APIAddr:               apiAddr,
+				APITimeout:            apiTimeout,
+				TraceAPIRequests:      traceCollector != "",
 			})
 
+			ctx, cancel := newRequestContext(cmd.Context())
+			defer cancel()
+
 			c := make(chan indexedResults, len(reqs))
 			for num, req := range reqs {
 				go func(num int, req *pb.StatSummaryRequest) {
-					resp, err := requestStatsFromAPI(client, req)
+					resp, err := requestStatsFromAPI(ctx, client, req)
 					rows := respToRows(resp)
 					c <- indexedResults{num, rows, err}
 				}(num, req)
 			}
 
 			totalRows := make([]*pb.StatTable_PodGroup_Row, 0)
+			var baselineRows []*pb.StatTable_PodGroup_Row
 			i := 0
 			for res := range c {
 				if res.err != nil {
 					fmt.Fprint(os.Stderr, res.err.Error())
 					os.Exit(1)
 				}
-				totalRows = append(totalRows, res.rows...)
+				if res.ix < numReqs {
+					totalRows = append(totalRows, res.rows...)
+				} else {
+					baselineRows = append(baselineRows, res.rows...)
+				}
 				if i++; i == len(reqs) {
 					close(c)
 				}
 			}
 
-			output := renderStatStats(totalRows, options)
+			output := renderStatStats(totalRows, baselineRows, options)
 			_, err = fmt.Print(output)
 
 			return err
@@ -243,7 +270,7 @@ If no resource name is specified, displays stats about all resources of the spec
 	}
 
 	cmd.PersistentFlags().StringVarP(&options.namespace, "namespace", "n", options.namespace, "Namespace of the specified resource")
-	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Stat window (for example: \"15s\", \"1m\", \"10m\", \"1h\"). Needs to be at least 15s.")
+	cmd.PersistentFlags().StringVarP(&options.timeWindow, "time-window", "t", options.timeWindow, "Stat window (for example: \"15s\", \"1m\", \"10m\", \"1h\", \"6h\"). Needs to be at least 15s and no longer than the Prometheus retention period.")
 	cmd.PersistentFlags().StringVar(&options.toResource, "to", options.toResource, "If present, restricts outbound stats to the specified resource name")
 	cmd.PersistentFlags().StringVar(&options.toNamespace, "to-namespace", options.toNamespace, "Sets the namespace used to lookup the \"--to\" resource; by default the current \"--namespace\" is used")
 	cmd.PersistentFlags().StringVar(&options.fromResource, "from", options.fromResource, "If present, restricts outbound stats from the specified resource name")
@@ -252,6 +279,9 @@ If no resource name is specified, displays stats about all resources of the spec
 	cmd.PersistentFlags().StringVarP(&options.outputFormat, "output", "o", options.outputFormat, "Output format; one of: \"table\" or \"json\" or \"wide\"")
 	cmd.PersistentFlags().StringVarP(&options.labelSelector, "selector", "l", options.labelSelector, "Selector (label query) to filter on, supports '=', '==', and '!='")
 	cmd.PersistentFlags().BoolVar(&options.unmeshed, "unmeshed", options.unmeshed, "If present, include unmeshed resources in the output")
+	cmd.PersistentFlags().StringVar(&options.direction, "direction", options.direction, "Direction of the traffic to display stats for; one of: \"inbound\" or \"outbound\"")
+	cmd.PersistentFlags().BoolVar(&options.totals, "totals", options.totals, "If present, append a TOTAL row to each table, summing the request rates and weighting the success rates by them")
+	cmd.PersistentFlags().StringVar(&options.compareWindow, "compare-window", options.compareWindow, "If present, also fetch stats over this longer window (for example: \"1h\"), and show how the success rate, request rate and p99 latency over --time-window differ from it")
 
 	pkgcmd.ConfigureNamespaceFlagCompletion(
 		cmd, []string{"namespace", "to-namespace", "from-namespace"},
@@ -269,8 +299,8 @@ func respToRows(resp *pb.StatSummaryResponse) []*pb.StatTable_PodGroup_Row {
 	return rows
 }
 
-func requestStatsFromAPI(client pb.ApiClient, req *pb.StatSummaryRequest) (*pb.StatSummaryResponse, error) {
-	resp, err := client.StatSummary(context.Background(), req)
+func requestStatsFromAPI(ctx context.Context, client pb.ApiClient, req *pb.StatSummaryRequest) (*pb.StatSummaryResponse, error) {
+	resp, err := client.StatSummary(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("StatSummary API error: %v", err)
 	}
@@ -281,10 +311,12 @@ func requestStatsFromAPI(client pb.ApiClient, req *pb.StatSummaryRequest) (*pb.S
 	return resp, nil
 }
 
-func renderStatStats(rows []*pb.StatTable_PodGroup_Row, options *statOptions) string {
+// renderStatStats renders the stats in rows. If --compare-window is set,
+// baselineRows hold the stats over that window, which rows are compared with.
+func renderStatStats(rows, baselineRows []*pb.StatTable_PodGroup_Row, options *statOptions) string {
 	var buffer bytes.Buffer
 	w := tabwriter.NewWriter(&buffer, 0, 0, padding, ' ', tabwriter.AlignRight)
-	writeStatsToBuffer(rows, w, options)
+	writeStatsToBuffer(rows, baselineRows, w, options)
 	w.Flush()
 
 	return renderStats(buffer, &options.statOptionsBase)
@@ -310,6 +342,9 @@ type row struct {
 	status string
 	*rowStats
 	*tsStats
+
+	// baseline holds the stats over --compare-window, if any
+	baseline *rowStats
 }
 
 type tsStats struct {
@@ -320,6 +355,7 @@ type tsStats struct {
 
 var (
 	nameHeader      = "NAME"
+	totalRowName    = "TOTAL"
 	namespaceHeader = "NAMESPACE"
 	apexHeader      = "APEX"
 	leafHeader      = "LEAF"
@@ -334,7 +370,7 @@ func isPodOwnerResource(typ string) bool {
 	return typ != k8s.TrafficSplit && typ != k8s.Authority
 }
 
-func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer, options *statOptions) {
+func writeStatsToBuffer(rows, baselineRows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer, options *statOptions) {
 	maxNameLength := len(nameHeader)
 	maxNamespaceLength := len(namespaceHeader)
 	maxApexLength := len(apexHeader)
@@ -372,10 +408,7 @@ func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer,
 		}
 
 		namespace := r.Resource.Namespace
-		key := fmt.Sprintf("%s/%s", namespace, name)
-		if r.Resource.Type == k8s.TrafficSplit {
-			key = fmt.Sprintf("%s/%s/%s", namespace, name, r.TsStats.Leaf)
-		}
+		key := statsKey(r)
 		resourceKey := r.Resource.Type
 
 		if _, ok := statTables[resourceKey]; !ok {
@@ -399,18 +432,7 @@ func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer,
 			status: r.Status,
 		}
 
-		if r.Stats != nil && statHasRequestData(r.Stats) {
-			statTables[resourceKey][key].rowStats = &rowStats{
-				requestRate:        getRequestRate(r.Stats.GetSuccessCount(), r.Stats.GetFailureCount(), r.TimeWindow),
-				successRate:        getSuccessRate(r.Stats.GetSuccessCount(), r.Stats.GetFailureCount()),
-				latencyP50:         r.Stats.LatencyMsP50,
-				latencyP95:         r.Stats.LatencyMsP95,
-				latencyP99:         r.Stats.LatencyMsP99,
-				tcpOpenConnections: r.GetTcpStats().GetOpenConnections(),
-				tcpReadBytes:       getByteRate(r.GetTcpStats().GetReadBytesTotal(), r.TimeWindow),
-				tcpWriteBytes:      getByteRate(r.GetTcpStats().GetWriteBytesTotal(), r.TimeWindow),
-			}
-		}
+		statTables[resourceKey][key].rowStats = newRowStats(r)
 		if r.TsStats != nil {
 			leaf := r.TsStats.Leaf
 			apex := r.TsStats.Apex
@@ -436,6 +458,14 @@ func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer,
 		}
 	}
 
+	// baseline rows are matched to the regular ones by resource, and ignored
+	// if the resource has no stats over --time-window
+	for _, r := range baselineRows {
+		if current, ok := statTables[r.Resource.Type][statsKey(r)]; ok {
+			current.baseline = newRowStats(r)
+		}
+	}
+
 	switch options.outputFormat {
 	case tableOutput, wideOutput:
 		if len(statTables) == 0 {
@@ -448,6 +478,44 @@ func writeStatsToBuffer(rows []*pb.StatTable_PodGroup_Row, w *tabwriter.Writer,
 	}
 }
 
+// statsKey returns the key identifying the resource of a row in its stat table
+func statsKey(r *pb.StatTable_PodGroup_Row) string {
+	if r.Resource.Type == k8s.TrafficSplit {
+		return fmt.Sprintf("%s/%s/%s", r.Resource.Namespace, r.Resource.Name, r.TsStats.Leaf)
+	}
+	return fmt.Sprintf("%s/%s", r.Resource.Namespace, r.Resource.Name)
+}
+
+// newRowStats returns the stats of a row, or nil if it has no request data
+func newRowStats(r *pb.StatTable_PodGroup_Row) *rowStats {
+	if r.Stats == nil || !statHasRequestData(r.Stats) {
+		return nil
+	}
+	return &rowStats{
+		requestRate:        getRequestRate(r.Stats.GetSuccessCount(), r.Stats.GetFailureCount(), r.TimeWindow),
+		successRate:        getSuccessRate(r.Stats.GetSuccessCount(), r.Stats.GetFailureCount()),
+		latencyP50:         r.Stats.LatencyMsP50,
+		latencyP95:         r.Stats.LatencyMsP95,
+		latencyP99:         r.Stats.LatencyMsP99,
+		tcpOpenConnections: r.GetTcpStats().GetOpenConnections(),
+		tcpReadBytes:       getByteRate(r.GetTcpStats().GetReadBytesTotal(), r.TimeWindow),
+		tcpWriteBytes:      getByteRate(r.GetTcpStats().GetWriteBytesTotal(), r.TimeWindow),
+	}
+}
+
+// statDeltas returns the formatted differences between the success rate,
+// request rate and p99 latency of current and baseline
+func statDeltas(current, baseline *rowStats) []interface{} {
+	if current == nil || baseline == nil {
+		return []interface{}{"-", "-", "-"}
+	}
+	return []interface{}{
+		fmt.Sprintf("%+.2f%%", (current.successRate-baseline.successRate)*100),
+		fmt.Sprintf("%+.1frps", current.requestRate-baseline.requestRate),
+		fmt.Sprintf("%+dms", int64(current.latencyP99)-int64(baseline.latencyP99)),
+	}
+}
+
 func printStatTables(statTables map[string]map[string]*row, w *tabwriter.Writer, maxNameLength, maxNamespaceLength, maxLeafLength, maxApexLength, maxWeightLength int, options *statOptions) {
 	usePrefix := false
 	if len(statTables) > 1 {
@@ -508,25 +576,43 @@ func printSingleStatTable(stats map[string]*row, resourceTypeLabel, resourceType
 		headers = append(headers, "MESHED")
 	}
 
-	headers = append(headers, []string{
+	statHeaders := []string{
 		"SUCCESS",
 		"RPS",
 		"LATENCY_P50",
 		"LATENCY_P95",
 		"LATENCY_P99",
-	}...)
+	}
 
 	if resourceType != k8s.TrafficSplit {
-		headers = append(headers, "TCP_CONN")
+		statHeaders = append(statHeaders, "TCP_CONN")
 	}
 
 	if showTCPBytes(options, resourceType) {
-		headers = append(headers, []string{
+		statHeaders = append(statHeaders, []string{
 			"READ_BYTES/SEC",
 			"WRITE_BYTES/SEC",
 		}...)
 	}
 
+	if options.compareWindow != "" {
+		statHeaders = append(statHeaders, []string{
+			"ΔSUCCESS",
+			"ΔRPS",
+			"ΔLATENCY_P99",
+		}...)
+	}
+
+	// prefix the stat columns so outbound stats can't be mistaken for the
+	// default inbound ones
+	if options.direction == outboundDirection {
+		for i, h := range statHeaders {
+			statHeaders[i] = "OUT_" + h
+		}
+	}
+
+	headers = append(headers, statHeaders...)
+
 	headers[len(headers)-1] = headers[len(headers)-1] + "\t" // trailing \t is required to format last column
 
 	fmt.Fprintln(w, strings.Join(headers, "\t"))
@@ -561,6 +647,11 @@ func printSingleStatTable(stats map[string]*row, resourceTypeLabel, resourceType
 			templateStringEmpty = templateStringEmpty + "-\t-\t"
 		}
 
+		if options.compareWindow != "" {
+			templateString = templateString + "%s\t%s\t%s\t"
+			templateStringEmpty = templateStringEmpty + "-\t-\t-\t"
+		}
+
 		if options.allNamespaces {
 			values = append(values,
 				namespace+strings.Repeat(" ", maxNamespaceLength-len(namespace)))
@@ -625,11 +716,96 @@ func printSingleStatTable(stats map[string]*row, resourceTypeLabel, resourceType
 				}...)
 			}
 
+			if options.compareWindow != "" {
+				values = append(values, statDeltas(stats[key].rowStats, stats[key].baseline)...)
+			}
+
 			fmt.Fprintf(w, templateString, values...)
 		} else {
 			fmt.Fprintf(w, templateStringEmpty, values...)
 		}
 	}
+
+	if options.totals {
+		printTotalRow(stats, resourceType, w, maxNameLength, maxNamespaceLength, maxLeafLength, maxApexLength, options)
+	}
+}
+
+// printTotalRow prints a row summing the request rates and TCP stats of all
+// the rows in stats. The success rate is the average of the rows' success
+// rates weighted by their request rates, and latencies can't be summed, so
+// they're left empty.
+func printTotalRow(stats map[string]*row, resourceType string, w *tabwriter.Writer, maxNameLength, maxNamespaceLength, maxLeafLength, maxApexLength int, options *statOptions) {
+	var total rowStats
+	var successes float64
+	hasStats := false
+	for _, r := range stats {
+		if r.rowStats == nil {
+			continue
+		}
+		hasStats = true
+		total.requestRate += r.requestRate
+		successes += r.successRate * r.requestRate
+		total.tcpOpenConnections += r.tcpOpenConnections
+		total.tcpReadBytes += r.tcpReadBytes
+		total.tcpWriteBytes += r.tcpWriteBytes
+	}
+
+	pad := func(s string, length int) string {
+		if length > len(s) {
+			return s + strings.Repeat(" ", length-len(s))
+		}
+		return s
+	}
+
+	cells := make([]string, 0)
+	if options.allNamespaces {
+		cells = append(cells, pad("-", maxNamespaceLength))
+	}
+	cells = append(cells, pad(totalRowName, maxNameLength))
+	if resourceType == k8s.Pod {
+		cells = append(cells, "-")
+	}
+	if resourceType == k8s.TrafficSplit {
+		cells = append(cells, pad("-", maxApexLength), pad("-", maxLeafLength), "-")
+	} else {
+		cells = append(cells, "-")
+	}
+
+	if !hasStats {
+		cells = append(cells, "-", "-")
+	} else {
+		successRate := "-"
+		if total.requestRate > 0 {
+			successRate = fmt.Sprintf("%.2f%%", successes/total.requestRate*100)
+		}
+		cells = append(cells, successRate, fmt.Sprintf("%.1frps", total.requestRate))
+	}
+	cells = append(cells, "-", "-", "-")
+
+	if showTCPConns(resourceType) {
+		if hasStats {
+			cells = append(cells, fmt.Sprintf("%d", total.tcpOpenConnections))
+		} else {
+			cells = append(cells, "-")
+		}
+	} else if resourceType == k8s.Authority {
+		cells = append(cells, "-")
+	}
+
+	if showTCPBytes(options, resourceType) {
+		if hasStats {
+			cells = append(cells, fmt.Sprintf("%.1fB/s", total.tcpReadBytes), fmt.Sprintf("%.1fB/s", total.tcpWriteBytes))
+		} else {
+			cells = append(cells, "-", "-")
+		}
+	}
+
+	if options.compareWindow != "" {
+		cells = append(cells, "-", "-", "-")
+	}
+
+	fmt.Fprintln(w, strings.Join(cells, "\t")+"\t")
 }
 
 func namespaceName(resourceType string, key string) (string, string) {
@@ -715,6 +891,34 @@ func getNamePrefix(resourceType string) string {
 	return canonicalType + "/"
 }
 
+// buildStatRequests builds the StatSummaryRequests for resources, followed by
+// the baseline ones over --compare-window if it's set. The baseline requests
+// are told apart by their index in the results: the first numReqs are the
+// regular ones.
+func buildStatRequests(resources []string, options *statOptions) (reqs []*pb.StatSummaryRequest, numReqs int, err error) {
+	reqs, err = buildStatSummaryRequests(resources, options)
+	if err != nil {
+		return nil, 0, err
+	}
+	numReqs = len(reqs)
+
+	if options.compareWindow != "" {
+		// the options were validated above; the baseline requests only
+		// differ by their window
+		baselineOptions := *options
+		baselineOptions.timeWindow = options.compareWindow
+		baselineOptions.compareWindow = ""
+		baselineOptions.totals = false
+		baselineReqs, err := buildStatSummaryRequests(resources, &baselineOptions)
+		if err != nil {
+			return nil, 0, err
+		}
+		reqs = append(reqs, baselineReqs...)
+	}
+
+	return reqs, numReqs, nil
+}
+
 func buildStatSummaryRequests(resources []string, options *statOptions) ([]*pb.StatSummaryRequest, error) {
 	targets, err := coreUtil.BuildResources(options.namespace, resources)
 	if err != nil {
@@ -762,6 +966,10 @@ func buildStatSummaryRequests(resources []string, options *statOptions) ([]*pb.S
 		if toRes != nil {
 			requestParams.ToName = toRes.Name
 			requestParams.ToType = toRes.Type
+		} else if options.direction == outboundDirection && fromRes == nil {
+			// an unnamed --to resource selects all the outbound traffic
+			// originating from the target
+			requestParams.ToType = target.Type
 		}
 
 		req, err := util.BuildStatSummaryRequest(requestParams)
@@ -797,9 +1005,58 @@ func (o *statOptions) validate(resourceType string) error {
 		}
 	}
 
+	err = o.validateDirection()
+	if err != nil {
+		return err
+	}
+
+	if o.totals && o.outputFormat == jsonOutput {
+		return fmt.Errorf("--totals is only supported with the table and wide output formats")
+	}
+
+	err = o.validateCompareWindow()
+	if err != nil {
+		return err
+	}
+
 	return o.validateOutputFormat()
 }
 
+func (o *statOptions) validateDirection() error {
+	switch o.direction {
+	case inboundDirection, outboundDirection:
+		return nil
+	default:
+		return fmt.Errorf("--direction currently only supports %s and %s", inboundDirection, outboundDirection)
+	}
+}
+
+// validateCompareWindow validates that --compare-window, if set, is longer
+// than --time-window and used with a table output format.
+func (o *statOptions) validateCompareWindow() error {
+	if o.compareWindow == "" {
+		return nil
+	}
+
+	if o.outputFormat == jsonOutput {
+		return fmt.Errorf("--compare-window is only supported with the table and wide output formats")
+	}
+
+	compareWindow, err := time.ParseDuration(o.compareWindow)
+	if err != nil {
+		return fmt.Errorf("invalid --compare-window: %s", err)
+	}
+	timeWindow, err := time.ParseDuration(o.timeWindow)
+	if err != nil {
+		return fmt.Errorf("invalid --time-window: %s", err)
+	}
+	if compareWindow <= timeWindow {
+		return fmt.Errorf("--compare-window (%s) needs to be longer than --time-window (%s)", o.compareWindow, o.timeWindow)
+	}
+
+	return nil
+}
+
 // validateConflictingFlags validates that the options do not contain mutually
 // exclusive flags.
 func (o *statOptions) validateConflictingFlags() error {
@@ -815,6 +1072,10 @@ func (o *statOptions) validateConflictingFlags() error {
 		return fmt.Errorf("--all-namespaces and --namespace flags are mutually exclusive")
 	}
 
+	if o.direction == outboundDirection && o.fromResource != "" {
+		return fmt.Errorf("--direction outbound and --from flags are mutually exclusive")
+	}
+
 	return nil
 }
 
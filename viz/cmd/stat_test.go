@@ -1,18 +1,21 @@
 package cmd
 
 import (
+	"context"
 	"testing"
 
 	pkgcmd "github.com/linkerd/linkerd2/pkg/cmd"
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	api "github.com/linkerd/linkerd2/viz/metrics-api"
+	pb "github.com/linkerd/linkerd2/viz/metrics-api/gen/viz"
 )
 
 type paramsExp struct {
-	counts  *api.PodCounts
-	options *statOptions
-	resNs   []string
-	file    string
+	counts       *api.PodCounts
+	options      *statOptions
+	resNs        []string
+	file         string
+	baselineRows []*pb.StatTable_PodGroup_Row
 }
 
 func TestStat(t *testing.T) {
@@ -89,6 +92,59 @@ func TestStat(t *testing.T) {
 		}, k8s.Namespace, t)
 	})
 
+	totalsOptions := newStatOptions()
+	totalsOptions.allNamespaces = true
+	totalsOptions.totals = true
+	t.Run("Returns all namespace stats with totals", func(t *testing.T) {
+		testStatCall(paramsExp{
+			counts: &api.PodCounts{
+				MeshedPods:  1,
+				RunningPods: 2,
+				FailedPods:  0,
+			},
+			options: totalsOptions,
+			resNs:   []string{"emojivoto1", "emojivoto2"},
+			file:    "stat_all_totals_output.golden",
+		}, k8s.Namespace, t)
+	})
+
+	compareOptions := newStatOptions()
+	compareOptions.allNamespaces = true
+	compareOptions.totals = true
+	compareOptions.compareWindow = "1h"
+	t.Run("Returns all namespace stats compared with a baseline window", func(t *testing.T) {
+		testStatCall(paramsExp{
+			counts: &api.PodCounts{
+				MeshedPods:  1,
+				RunningPods: 2,
+				FailedPods:  0,
+			},
+			options: compareOptions,
+			resNs:   []string{"emojivoto1", "emojivoto2"},
+			file:    "stat_all_compare_output.golden",
+			// no baseline for emojivoto2, whose deltas are left empty
+			baselineRows: []*pb.StatTable_PodGroup_Row{
+				{
+					Resource: &pb.Resource{
+						Namespace: "emojivoto1",
+						Type:      k8s.Namespace,
+						Name:      "emoji",
+					},
+					TimeWindow:      "1h",
+					MeshedPodCount:  1,
+					RunningPodCount: 2,
+					Stats: &pb.BasicStats{
+						SuccessCount: 5400,
+						FailureCount: 1800,
+						LatencyMsP50: 100,
+						LatencyMsP95: 140,
+						LatencyMsP99: 150,
+					},
+				},
+			},
+		}, k8s.Namespace, t)
+	})
+
 	options.outputFormat = jsonOutput
 	t.Run("Returns all namespace stats (json)", func(t *testing.T) {
 		testStatCall(paramsExp{
@@ -118,6 +174,21 @@ func TestStat(t *testing.T) {
 		}, k8s.Namespace, t)
 	})
 
+	options = newStatOptions()
+	options.direction = outboundDirection
+	t.Run("Returns outbound stats", func(t *testing.T) {
+		testStatCall(paramsExp{
+			counts: &api.PodCounts{
+				MeshedPods:  1,
+				RunningPods: 2,
+				FailedPods:  0,
+			},
+			options: options,
+			resNs:   []string{"emojivoto1"},
+			file:    "stat_one_outbound_output.golden",
+		}, k8s.Namespace, t)
+	})
+
 	t.Run("Returns an error for named resource queries with the --all-namespaces flag", func(t *testing.T) {
 		options := newStatOptions()
 		options.allNamespaces = true
@@ -181,6 +252,105 @@ func TestStat(t *testing.T) {
 		}
 	})
 
+	t.Run("Rejects commands with both --direction outbound and --from flags", func(t *testing.T) {
+		options := newStatOptions()
+		if options.namespace == "" {
+			options.namespace = pkgcmd.GetDefaultNamespace(kubeconfigPath, kubeContext)
+		}
+		options.direction = outboundDirection
+		options.fromResource = "deploy/bar"
+		args := []string{"po"}
+		expectedError := "--direction outbound and --from flags are mutually exclusive"
+
+		_, err := buildStatSummaryRequests(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects invalid --direction values", func(t *testing.T) {
+		options := newStatOptions()
+		if options.namespace == "" {
+			options.namespace = pkgcmd.GetDefaultNamespace(kubeconfigPath, kubeContext)
+		}
+		options.direction = "sideways"
+		args := []string{"po"}
+		expectedError := "--direction currently only supports inbound and outbound"
+
+		_, err := buildStatSummaryRequests(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects --totals with json output", func(t *testing.T) {
+		options := newStatOptions()
+		if options.namespace == "" {
+			options.namespace = pkgcmd.GetDefaultNamespace(kubeconfigPath, kubeContext)
+		}
+		options.totals = true
+		options.outputFormat = jsonOutput
+		args := []string{"po"}
+		expectedError := "--totals is only supported with the table and wide output formats"
+
+		_, err := buildStatSummaryRequests(args, options)
+		if err == nil || err.Error() != expectedError {
+			t.Fatalf("Expected error [%s] instead got [%s]", expectedError, err)
+		}
+	})
+
+	t.Run("Rejects invalid --compare-window values", func(t *testing.T) {
+		testCases := []struct {
+			compareWindow string
+			outputFormat  string
+			expectedError string
+		}{
+			{"1h", jsonOutput, "--compare-window is only supported with the table and wide output formats"},
+			{"1m", tableOutput, "--compare-window (1m) needs to be longer than --time-window (1m)"},
+			{"soon", tableOutput, "invalid --compare-window: time: invalid duration \"soon\""},
+		}
+
+		for _, tc := range testCases {
+			tc := tc // pin
+			t.Run(tc.compareWindow, func(t *testing.T) {
+				options := newStatOptions()
+				if options.namespace == "" {
+					options.namespace = pkgcmd.GetDefaultNamespace(kubeconfigPath, kubeContext)
+				}
+				options.compareWindow = tc.compareWindow
+				options.outputFormat = tc.outputFormat
+				args := []string{"po"}
+
+				_, err := buildStatSummaryRequests(args, options)
+				if err == nil || err.Error() != tc.expectedError {
+					t.Fatalf("Expected error [%s] instead got [%s]", tc.expectedError, err)
+				}
+			})
+		}
+	})
+
+	t.Run("Builds baseline requests over --compare-window", func(t *testing.T) {
+		options := newStatOptions()
+		options.namespace = "emojivoto"
+		options.timeWindow = "1m"
+		options.compareWindow = "1h"
+		args := []string{"deploy"}
+
+		reqs, numReqs, err := buildStatRequests(args, options)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if numReqs != 1 || len(reqs) != 2 {
+			t.Fatalf("Expected 1 regular and 1 baseline request, got %d of %d", numReqs, len(reqs))
+		}
+		if reqs[0].TimeWindow != "1m" {
+			t.Fatalf("Expected the regular request over 1m, got %s", reqs[0].TimeWindow)
+		}
+		if reqs[1].TimeWindow != "1h" {
+			t.Fatalf("Expected the baseline request over 1h, got %s", reqs[1].TimeWindow)
+		}
+	})
+
 	t.Run("Rejects --to-namespace flag when the target is a namespace", func(t *testing.T) {
 		options := newStatOptions()
 		if options.namespace == "" {
@@ -248,13 +418,13 @@ func testStatCall(exp paramsExp, resourceType string, t *testing.T) {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 
-	resp, err := requestStatsFromAPI(mockClient, reqs[0])
+	resp, err := requestStatsFromAPI(context.Background(), mockClient, reqs[0])
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 
 	rows := respToRows(resp)
-	output := renderStatStats(rows, exp.options)
+	output := renderStatStats(rows, exp.baselineRows, exp.options)
 
 	testDataDiffer.DiffTestdata(t, exp.file, output)
 }
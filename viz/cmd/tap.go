@@ -4,10 +4,12 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/golang/protobuf/ptypes/duration"
 	netPb "github.com/linkerd/linkerd2/controller/gen/common/net"
@@ -25,6 +27,9 @@ import (
 	"google.golang.org/grpc/codes"
 )
 
+// jsonLinesOutput renders each tap event as a JSON object on its own line
+const jsonLinesOutput = "jsonl"
+
 type renderTapEventFunc func(*tapPb.TapEvent, string) string
 
 type tapOptions struct {
@@ -104,6 +109,14 @@ type tapEvent struct {
 	RequestInitEvent  *requestInitEvent  `json:"requestInitEvent,omitempty"`
 	ResponseInitEvent *responseInitEvent `json:"responseInitEvent,omitempty"`
 	ResponseEndEvent  *responseEndEvent  `json:"responseEndEvent,omitempty"`
+	Target            string             `json:"target,omitempty"`
+}
+
+// taggedTapEvent is an event received when tapping several resources at
+// once, along with the index of the resource it was tapped from
+type taggedTapEvent struct {
+	event *tapPb.TapEvent
+	index int
 }
 
 func newTapOptions() *tapOptions {
@@ -121,7 +134,7 @@ func newTapOptions() *tapOptions {
 }
 
 func (o *tapOptions) validate() error {
-	if o.output == "" || o.output == wideOutput || o.output == jsonOutput {
+	if o.output == "" || o.output == wideOutput || o.output == jsonOutput || o.output == jsonLinesOutput {
 		return nil
 	}
 
@@ -163,7 +176,10 @@ func NewCmdTap() *cobra.Command {
   * replicasets
   * replicationcontrollers
   * statefulsets
-  * services (only supported as a --to resource)`,
+  * services (only supported as a --to resource)
+
+  Several TYPE/NAME resources can be tapped at once, in which case their
+  events are interleaved and tagged with the resource they were tapped from.`,
 		Example: `  # tap the web deployment in the default namespace
   linkerd viz tap deploy/web
 
@@ -171,8 +187,11 @@ func NewCmdTap() *cobra.Command {
   linkerd viz tap pod/web-dlbvj
 
   # tap the test namespace, filter by request to prod namespace
-  linkerd viz tap ns/test --to ns/prod`,
-		Args: cobra.RangeArgs(1, 2),
+  linkerd viz tap ns/test --to ns/prod
+
+  # tap both the web and voting deployments
+  linkerd viz tap deploy/web deploy/voting`,
+		Args: cobra.MinimumNArgs(1),
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			// This command requires at most two arguments if we already have
 			// two after requesting autocompletion i.e. [tab][tab]
@@ -211,31 +230,37 @@ func NewCmdTap() *cobra.Command {
 				ImpersonateGroup:      impersonateGroup,
 				KubeContext:           kubeContext,
 				APIAddr:               apiAddr,
+				APITimeout:            apiTimeout,
+				TraceAPIRequests:      traceCollector != "",
 			})
 
-			requestParams := pkg.TapRequestParams{
-				Resource:      strings.Join(args, "/"),
-				Namespace:     options.namespace,
-				ToResource:    options.toResource,
-				ToNamespace:   options.toNamespace,
-				MaxRps:        options.maxRps,
-				Scheme:        options.scheme,
-				Method:        options.method,
-				Authority:     options.authority,
-				Path:          options.path,
-				Extract:       options.output == jsonOutput,
-				LabelSelector: options.labelSelector,
-			}
-
 			err := options.validate()
 			if err != nil {
 				return fmt.Errorf("validation error when executing tap command: %v", err)
 			}
 
-			req, err := pkg.BuildTapByResourceRequest(requestParams)
-			if err != nil {
-				fmt.Fprint(os.Stderr, err.Error())
-				os.Exit(1)
+			resources := tapResources(args)
+			reqs := make([]*tapPb.TapByResourceRequest, len(resources))
+			for i, resource := range resources {
+				requestParams := pkg.TapRequestParams{
+					Resource:      resource,
+					Namespace:     options.namespace,
+					ToResource:    options.toResource,
+					ToNamespace:   options.toNamespace,
+					MaxRps:        options.maxRps,
+					Scheme:        options.scheme,
+					Method:        options.method,
+					Authority:     options.authority,
+					Path:          options.path,
+					Extract:       options.output == jsonOutput || options.output == jsonLinesOutput,
+					LabelSelector: options.labelSelector,
+				}
+
+				reqs[i], err = pkg.BuildTapByResourceRequest(requestParams)
+				if err != nil {
+					fmt.Fprint(os.Stderr, err.Error())
+					os.Exit(1)
+				}
 			}
 
 			k8sAPI, err := k8s.NewAPI(kubeconfigPath, kubeContext, impersonate, impersonateGroup, 0)
@@ -244,7 +269,11 @@ func NewCmdTap() *cobra.Command {
 				os.Exit(1)
 			}
 
-			err = requestTapByResourceFromAPI(cmd.Context(), os.Stdout, k8sAPI, req, options)
+			if len(reqs) == 1 {
+				err = requestTapByResourceFromAPI(cmd.Context(), os.Stdout, k8sAPI, reqs[0], options)
+			} else {
+				err = requestTapByResourcesFromAPI(cmd.Context(), os.Stdout, k8sAPI, resources, reqs, options)
+			}
 			if err != nil {
 				fmt.Fprint(os.Stderr, err.Error())
 				os.Exit(1)
@@ -271,7 +300,7 @@ func NewCmdTap() *cobra.Command {
 	cmd.PersistentFlags().StringVar(&options.path, "path", options.path,
 		"Display requests with paths that start with this prefix")
 	cmd.PersistentFlags().StringVarP(&options.output, "output", "o", options.output,
-		fmt.Sprintf("Output format. One of: \"%s\", \"%s\"", wideOutput, jsonOutput))
+		fmt.Sprintf("Output format. One of: \"%s\", \"%s\", \"%s\" (one JSON object per line)", wideOutput, jsonOutput, jsonLinesOutput))
 	cmd.PersistentFlags().StringVarP(&options.labelSelector, "selector", "l", options.labelSelector,
 		"Selector (label query) to filter on, supports '=', '==', and '!='")
 
@@ -291,6 +320,107 @@ func requestTapByResourceFromAPI(ctx context.Context, w io.Writer, k8sAPI *k8s.K
 	return writeTapEventsToBuffer(w, reader, req, options)
 }
 
+// tapResources returns the resources to tap given the command arguments,
+// which are either a single "TYPE [NAME]" resource or any number of
+// "TYPE/NAME" resources
+func tapResources(args []string) []string {
+	if len(args) == 2 && !strings.Contains(args[0], "/") && !strings.Contains(args[1], "/") {
+		return []string{strings.Join(args, "/")}
+	}
+	return args
+}
+
+// requestTapByResourcesFromAPI taps several resources at once, and renders
+// their events in the order they're received
+func requestTapByResourcesFromAPI(ctx context.Context, w io.Writer, k8sAPI *k8s.KubernetesAPI, resources []string, reqs []*tapPb.TapByResourceRequest, options *tapOptions) error {
+	readers := make([]*bufio.Reader, len(reqs))
+	for i, req := range reqs {
+		reader, body, err := pkg.Reader(ctx, k8sAPI, req)
+		if err != nil {
+			return fmt.Errorf("failed to tap %s: %s", resources[i], err)
+		}
+		defer body.Close()
+		readers[i] = reader
+	}
+
+	return renderMultiTapEvents(readers, w, resources, reqs, options)
+}
+
+// renderMultiTapEvents reads the events of every tap stream concurrently and
+// renders them interleaved, each one tagged with the resource it was tapped
+// from. Stream failures are reported as they happen, and returned once all
+// the streams have ended.
+func renderMultiTapEvents(tapByteStreams []*bufio.Reader, w io.Writer, resources []string, reqs []*tapPb.TapByResourceRequest, options *tapOptions) error {
+	events := make(chan taggedTapEvent)
+	done := make(chan struct{})
+	defer close(done)
+
+	// each stream only writes its own error, and they're read after wg.Wait
+	streamErrs := make([]error, len(tapByteStreams))
+	var wg sync.WaitGroup
+	for i, tapByteStream := range tapByteStreams {
+		wg.Add(1)
+		go func(i int, tapByteStream *bufio.Reader) {
+			defer wg.Done()
+			for {
+				event := tapPb.TapEvent{}
+				err := protohttp.FromByteStreamToProtocolBuffers(tapByteStream, &event)
+				if errors.Is(err, io.EOF) {
+					return
+				}
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "%s: %s\n", resources[i], err)
+					streamErrs[i] = err
+					return
+				}
+				select {
+				case events <- taggedTapEvent{event: &event, index: i}:
+				case <-done:
+					return
+				}
+			}
+		}(i, tapByteStream)
+	}
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	for e := range events {
+		resource := resources[e.index]
+		var rendered string
+		switch options.output {
+		case "":
+			rendered = fmt.Sprintf("[%s] %s", resource, renderTapEvent(e.event, ""))
+		case wideOutput:
+			resourceType := reqs[e.index].GetTarget().GetResource().GetType()
+			rendered = fmt.Sprintf("[%s] %s", resource, renderTapEvent(e.event, resourceType))
+		case jsonOutput:
+			m := mapPublicToDisplayTapEvent(e.event)
+			m.Target = resource
+			rendered = marshalTapEventJSON(m, false)
+		case jsonLinesOutput:
+			m := mapPublicToDisplayTapEvent(e.event)
+			m.Target = resource
+			rendered = marshalTapEventJSON(m, true)
+		}
+		if _, err := fmt.Fprintln(w, rendered); err != nil {
+			return err
+		}
+	}
+
+	var failed []string
+	for i, err := range streamErrs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", resources[i], err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to tap %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
 func writeTapEventsToBuffer(w io.Writer, tapByteStream *bufio.Reader, req *tapPb.TapByResourceRequest, options *tapOptions) error {
 	var err error
 	switch options.output {
@@ -301,6 +431,8 @@ func writeTapEventsToBuffer(w io.Writer, tapByteStream *bufio.Reader, req *tapPb
 		err = renderTapEvents(tapByteStream, w, renderTapEvent, resource)
 	case jsonOutput:
 		err = renderTapEvents(tapByteStream, w, renderTapEventJSON, "")
+	case jsonLinesOutput:
+		err = renderTapEvents(tapByteStream, w, renderTapEventJSONLine, "")
 	}
 	if err != nil {
 		return err
@@ -432,8 +564,23 @@ func renderTapEvent(event *tapPb.TapEvent, resource string) string {
 
 // renderTapEventJSON renders a Public API TapEvent to a string in JSON format.
 func renderTapEventJSON(event *tapPb.TapEvent, _ string) string {
-	m := mapPublicToDisplayTapEvent(event)
-	e, err := json.MarshalIndent(m, "", "  ")
+	return marshalTapEventJSON(mapPublicToDisplayTapEvent(event), false)
+}
+
+// renderTapEventJSONLine renders a tap event as a single-line JSON object, so
+// that the stream can be consumed as newline-delimited JSON
+func renderTapEventJSONLine(event *tapPb.TapEvent, _ string) string {
+	return marshalTapEventJSON(mapPublicToDisplayTapEvent(event), true)
+}
+
+func marshalTapEventJSON(m *tapEvent, compact bool) string {
+	var e []byte
+	var err error
+	if compact {
+		e, err = json.Marshal(m)
+	} else {
+		e, err = json.MarshalIndent(m, "", "  ")
+	}
 	if err != nil {
 		return fmt.Sprintf("{\"error marshalling JSON\": \"%s\"}", err)
 	}
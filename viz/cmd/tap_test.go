@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/duration"
 	netPb "github.com/linkerd/linkerd2/controller/gen/common/net"
 	"github.com/linkerd/linkerd2/pkg/addr"
@@ -145,6 +152,8 @@ func busyTest(t *testing.T, output string) {
 		goldenFilePath = "testdata/tap_busy_output_wide.golden"
 	case jsonOutput:
 		goldenFilePath = "testdata/tap_busy_output_json.golden"
+	case jsonLinesOutput:
+		goldenFilePath = "testdata/tap_busy_output_jsonl.golden"
 	default:
 		goldenFilePath = "testdata/tap_busy_output.golden"
 	}
@@ -175,6 +184,10 @@ func TestRequestTapByResourceFromAPI(t *testing.T) {
 		busyTest(t, "json")
 	})
 
+	t.Run("Should render JSON lines busy response if everything went well", func(t *testing.T) {
+		busyTest(t, "jsonl")
+	})
+
 	t.Run("Should render empty response if no events returned", func(t *testing.T) {
 		resourceType := k8s.Pod
 		params := pkg.TapRequestParams{
@@ -411,3 +424,125 @@ func TestEventToString(t *testing.T) {
 		}
 	})
 }
+
+func TestTapResources(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"deploy"}, []string{"deploy"}},
+		{[]string{"deploy/web"}, []string{"deploy/web"}},
+		{[]string{"deploy", "web"}, []string{"deploy/web"}},
+		{[]string{"deploy/web", "deploy/voting"}, []string{"deploy/web", "deploy/voting"}},
+		{[]string{"deploy/web", "po/emoji", "ns/test"}, []string{"deploy/web", "po/emoji", "ns/test"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc // pin
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			resources := tapResources(tc.args)
+			if !reflect.DeepEqual(resources, tc.expected) {
+				t.Fatalf("Expected resources %v, got %v", tc.expected, resources)
+			}
+		})
+	}
+}
+
+func TestRenderMultiTapEvents(t *testing.T) {
+	resources := []string{"deploy/web", "po/emoji"}
+	reqs := make([]*tapPb.TapByResourceRequest, len(resources))
+	payloads := make([][]byte, len(resources))
+	for i, resource := range resources {
+		req, err := pkg.BuildTapByResourceRequest(pkg.TapRequestParams{Resource: resource})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		reqs[i] = req
+
+		event := pkg.CreateTapEvent(
+			&tapPb.TapEvent_Http{
+				Event: &tapPb.TapEvent_Http_RequestInit_{
+					RequestInit: &tapPb.TapEvent_Http_RequestInit{
+						Id:        &tapPb.TapEvent_Http_StreamId{Base: uint32(i)},
+						Authority: "localhost",
+						Path:      "/" + resource,
+					},
+				},
+			},
+			map[string]string{},
+			tapPb.TapEvent_OUTBOUND,
+		)
+		payload, err := proto.Marshal(event)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		payloads[i] = protohttp.SerializeAsPayload(payload)
+	}
+	newStreams := func() []*bufio.Reader {
+		streams := make([]*bufio.Reader, len(payloads))
+		for i, payload := range payloads {
+			streams[i] = bufio.NewReader(bytes.NewReader(payload))
+		}
+		return streams
+	}
+
+	t.Run("Tags text output with the tapped resource", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := renderMultiTapEvents(newStreams(), &buf, resources, reqs, &tapOptions{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != len(resources) {
+			t.Fatalf("Expected %d events, got %d: %v", len(resources), len(lines), lines)
+		}
+		sort.Strings(lines)
+		for i, resource := range resources {
+			prefix := fmt.Sprintf("[%s] req id=%d:0", resource, i)
+			if !strings.HasPrefix(lines[i], prefix) {
+				t.Fatalf("Expected event to start with %q, got %q", prefix, lines[i])
+			}
+		}
+	})
+
+	t.Run("Returns the errors of failed streams", func(t *testing.T) {
+		streams := newStreams()
+		// a truncated payload fails to decode
+		streams[1] = bufio.NewReader(bytes.NewReader(payloads[1][:len(payloads[1])-1]))
+
+		var buf bytes.Buffer
+		err := renderMultiTapEvents(streams, &buf, resources, reqs, &tapOptions{})
+		if err == nil || !strings.HasPrefix(err.Error(), fmt.Sprintf("failed to tap %s (", resources[1])) {
+			t.Fatalf("Expected an error for %s, got %v", resources[1], err)
+		}
+
+		// the other streams are still rendered
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != len(resources)-1 {
+			t.Fatalf("Expected %d events, got %d: %v", len(resources)-1, len(lines), lines)
+		}
+	})
+
+	t.Run("Sets the target of JSON events", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := renderMultiTapEvents(newStreams(), &buf, resources, reqs, &tapOptions{output: jsonOutput})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		targets := []string{}
+		decoder := json.NewDecoder(&buf)
+		for decoder.More() {
+			var event tapEvent
+			if err := decoder.Decode(&event); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			targets = append(targets, event.Target)
+		}
+		sort.Strings(targets)
+		if !reflect.DeepEqual(targets, resources) {
+			t.Fatalf("Expected targets %v, got %v", resources, targets)
+		}
+	})
+}
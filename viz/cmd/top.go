@@ -358,6 +358,8 @@ func NewCmdTop() *cobra.Command {
 				ImpersonateGroup:      impersonateGroup,
 				KubeContext:           kubeContext,
 				APIAddr:               apiAddr,
+				APITimeout:            apiTimeout,
+				TraceAPIRequests:      traceCollector != "",
 			})
 
 			requestParams := pkg.TapRequestParams{
@@ -4,9 +4,12 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/linkerd/linkerd2/pkg/k8s"
@@ -85,6 +88,10 @@ func (c *grpcOverHTTPClient) apiRequest(ctx context.Context, endpoint string, re
 	log.Debugf("Making gRPC-over-HTTP call to [%s] [%+v]", url.String(), req)
 	httpRsp, err := c.post(ctx, url, req)
 	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return fmt.Errorf("timed out connecting to the viz API after %s: %w", c.httpClient.Timeout, err)
+		}
 		return err
 	}
 	defer httpRsp.Body.Close()
@@ -157,6 +164,28 @@ func NewInternalClient(namespace string, kubeAPIHost string) (pb.ApiClient, erro
 // NewExternalClient creates a new Viz API client intended to run from
 // outside a Kubernetes cluster.
 func NewExternalClient(ctx context.Context, namespace string, kubeAPI *k8s.KubernetesAPI) (pb.ApiClient, error) {
+	return NewExternalClientWithOptions(ctx, namespace, kubeAPI, ExternalClientOptions{})
+}
+
+// ExternalClientOptions holds the settings of a client created by
+// NewExternalClientWithOptions
+type ExternalClientOptions struct {
+	// Timeout is the time allowed for each request; zero means no timeout
+	Timeout time.Duration
+
+	// Trace, if set, wraps the transport so that requests are traced. It's
+	// only useful once tracing has been initialized with a collector address.
+	Trace bool
+
+	// WrapTransport, if set, is called with the transport used to reach the
+	// API, and its result is used instead. This allows adding middleware,
+	// e.g. for logging or tracing, or replacing the transport altogether.
+	WrapTransport func(http.RoundTripper) http.RoundTripper
+}
+
+// NewExternalClientWithOptions is like NewExternalClient, with the HTTP
+// client configured according to opts.
+func NewExternalClientWithOptions(ctx context.Context, namespace string, kubeAPI *k8s.KubernetesAPI, opts ExternalClientOptions) (pb.ApiClient, error) {
 	portforward, err := k8s.NewPortForward(
 		ctx,
 		kubeAPI,
@@ -184,6 +213,18 @@ func NewExternalClient(ctx context.Context, namespace string, kubeAPI *k8s.Kuber
 	if err != nil {
 		return nil, err
 	}
+	configureHTTPClient(httpClientToUse, opts)
 
 	return newClient(apiURL, httpClientToUse, namespace)
 }
+
+// configureHTTPClient applies opts to httpClient
+func configureHTTPClient(httpClient *http.Client, opts ExternalClientOptions) {
+	httpClient.Timeout = opts.Timeout
+	if opts.Trace {
+		httpClient.Transport = &ochttp.Transport{Base: httpClient.Transport}
+	}
+	if opts.WrapTransport != nil {
+		httpClient.Transport = opts.WrapTransport(httpClient.Transport)
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/linkerd/linkerd2/viz/metrics-api/gen/viz"
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/trace"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	apiURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	client, err := newClient(apiURL, &http.Client{Timeout: 10 * time.Millisecond}, "linkerd-viz")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, err = client.SelfCheck(context.Background(), &pb.SelfCheckRequest{})
+	if err == nil {
+		t.Fatal("Expected an error, got nothing")
+	}
+	expectedPrefix := "timed out connecting to the viz API after 10ms"
+	if !strings.HasPrefix(err.Error(), expectedPrefix) {
+		t.Fatalf("Expected error starting with %q, got %q", expectedPrefix, err)
+	}
+}
+
+// roundTripperFunc adapts a function to the http.RoundTripper interface
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWrapTransport(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	apiURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var paths []string
+	httpClient := &http.Client{Transport: http.DefaultTransport}
+	configureHTTPClient(httpClient, ExternalClientOptions{
+		WrapTransport: func(rt http.RoundTripper) http.RoundTripper {
+			if rt != http.DefaultTransport {
+				t.Fatalf("Expected the original transport to be wrapped, got %v", rt)
+			}
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				paths = append(paths, req.URL.Path)
+				return rt.RoundTrip(req)
+			})
+		},
+	})
+
+	client, err := newClient(apiURL, httpClient, "linkerd-viz")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	// the test server fails every request; only the wrapping matters here
+	client.SelfCheck(context.Background(), &pb.SelfCheckRequest{})
+
+	expectedPath := "/api/v1/SelfCheck"
+	if len(paths) != 1 || paths[0] != expectedPath {
+		t.Fatalf("Expected a single request to %s through the wrapped transport, got %v", expectedPath, paths)
+	}
+}
+
+func TestTracePropagation(t *testing.T) {
+	traceIDs := make(chan string, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceIDs <- r.Header.Get("X-B3-TraceId")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	apiURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	for _, traced := range []bool{true, false} {
+		traced := traced // pin
+		t.Run(fmt.Sprintf("traced=%t", traced), func(t *testing.T) {
+			httpClient := &http.Client{Transport: http.DefaultTransport}
+			configureHTTPClient(httpClient, ExternalClientOptions{Trace: traced})
+			if _, ok := httpClient.Transport.(*ochttp.Transport); ok != traced {
+				t.Fatalf("Expected the transport to be traced: %t, got %v", traced, httpClient.Transport)
+			}
+
+			client, err := newClient(apiURL, httpClient, "linkerd-viz")
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			ctx, span := trace.StartSpan(context.Background(), "test", trace.WithSampler(trace.AlwaysSample()))
+			defer span.End()
+
+			// the test server fails every request; only the headers matter here
+			client.SelfCheck(ctx, &pb.SelfCheckRequest{})
+
+			expected := ""
+			if traced {
+				expected = span.SpanContext().TraceID.String()
+			}
+			if traceID := <-traceIDs; traceID != expected {
+				t.Fatalf("Expected the request to carry trace ID %q, got %q", expected, traceID)
+			}
+		})
+	}
+}
@@ -30,6 +30,7 @@ type grpcServer struct {
 	controllerNamespace string
 	clusterDomain       string
 	ignoredNamespaces   []string
+	retention           *promRetention
 }
 
 type podReport struct {
@@ -59,6 +60,7 @@ func newGrpcServer(
 		controllerNamespace: controllerNamespace,
 		clusterDomain:       clusterDomain,
 		ignoredNamespaces:   ignoredNamespaces,
+		retention:           &promRetention{},
 	}
 
 	pb.RegisterApiServer(prometheus.NewGrpcServer(), grpcServer)
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"net"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	vizClient "github.com/linkerd/linkerd2/viz/metrics-api/client"
@@ -139,3 +141,52 @@ func assertCallWasForwarded(t *testing.T, mockServer *mockServer, expectedReques
 		t.Fatalf("Expecting error, got nothing")
 	}
 }
+
+func TestMockAPIHTTPServer(t *testing.T) {
+	mockServer := &MockAPIServer{
+		StatSummaryResponseToReturn: GenStatSummaryResponse("emoji", "deployment", []string{"emojivoto"}, nil, true, true),
+		SelfCheckResponseToReturn:   &pb.SelfCheckResponse{},
+	}
+	ts := NewMockAPIHTTPServer(mockServer)
+	defer ts.Close()
+
+	client, err := vizClient.NewInternalClient("linkerd-viz", strings.TrimPrefix(ts.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Run("Returns the canned responses", func(t *testing.T) {
+		rsp, err := client.StatSummary(context.Background(), &pb.StatSummaryRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !proto.Equal(rsp, mockServer.StatSummaryResponseToReturn) {
+			t.Fatalf("Expected response [%v], got [%v]", mockServer.StatSummaryResponseToReturn, rsp)
+		}
+	})
+
+	t.Run("Returns the canned error", func(t *testing.T) {
+		mockServer.ErrorToReturn = errors.New("expected")
+		defer func() { mockServer.ErrorToReturn = nil }()
+
+		_, err := client.SelfCheck(context.Background(), &pb.SelfCheckRequest{})
+		if err == nil || !strings.Contains(err.Error(), "expected") {
+			t.Fatalf("Expected error containing \"expected\", got %v", err)
+		}
+	})
+
+	t.Run("Lets slow calls time out", func(t *testing.T) {
+		mockServer.Delay = time.Minute
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := client.SelfCheck(ctx, &pb.SelfCheckRequest{})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected a deadline exceeded error, got %v", err)
+		}
+	})
+
+	if calls := mockServer.Calls(); calls != 3 {
+		t.Fatalf("Expected the server to receive 3 calls, got %d", calls)
+	}
+}
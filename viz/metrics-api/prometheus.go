@@ -7,10 +7,12 @@ import (
 	"math"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/linkerd/linkerd2/pkg/k8s"
 	pb "github.com/linkerd/linkerd2/viz/metrics-api/gen/viz"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 	log "github.com/sirupsen/logrus"
 	"go.opencensus.io/trace"
@@ -39,6 +41,9 @@ const (
 	gatewayNameLabel       = model.LabelName("gateway_name")
 	gatewayNamespaceLabel  = model.LabelName("gateway_namespace")
 	remoteClusterNameLabel = model.LabelName("target_cluster_name")
+
+	promRetentionFlag = "storage.tsdb.retention.time"
+	promRetentionTTL  = 5 * time.Minute
 )
 
 var (
@@ -242,3 +247,62 @@ func (s *grpcServer) getPrometheusMetrics(ctx context.Context, requestQueries ma
 
 	return results, nil
 }
+
+// promRetention caches the retention period of the Prometheus instance, so
+// that it's only fetched once per promRetentionTTL rather than on each request
+type promRetention struct {
+	sync.Mutex
+	// value is zero if the retention couldn't be determined
+	value     model.Duration
+	fetchedAt time.Time
+}
+
+// get returns the cached retention, fetching it from promAPI if it's older
+// than promRetentionTTL
+func (r *promRetention) get(ctx context.Context, promAPI promv1.API) model.Duration {
+	r.Lock()
+	defer r.Unlock()
+
+	if !r.fetchedAt.IsZero() && time.Since(r.fetchedAt) < promRetentionTTL {
+		return r.value
+	}
+
+	r.value = 0
+	r.fetchedAt = time.Now()
+	flags, err := promAPI.Flags(ctx)
+	if err != nil {
+		log.Debugf("Failed to retrieve Prometheus flags: %s", err)
+		return 0
+	}
+	if retention, err := model.ParseDuration(flags[promRetentionFlag]); err == nil {
+		r.value = retention
+	}
+	return r.value
+}
+
+// checkTimeWindow returns an error if timeWindow is longer than the retention
+// of the Prometheus instance backing this server, in which case part of the
+// window would silently hold no data. The check is skipped if the retention
+// can't be determined, e.g. for Prometheus instances not exposing their flags.
+//
+// Edges requests aren't checked as they don't take a time window.
+func (s *grpcServer) checkTimeWindow(ctx context.Context, timeWindow string) error {
+	if s.prometheusAPI == nil || timeWindow == "" {
+		return nil
+	}
+
+	window, err := model.ParseDuration(timeWindow)
+	if err != nil {
+		return fmt.Errorf("invalid time window %q: %s", timeWindow, err)
+	}
+
+	retention := s.retention.get(ctx, s.prometheusAPI)
+	if retention == 0 {
+		return nil
+	}
+
+	if window > retention {
+		return fmt.Errorf("time window %s exceeds the Prometheus retention period of %s", timeWindow, retention)
+	}
+	return nil
+}
@@ -92,6 +92,10 @@ func (s *grpcServer) StatSummary(ctx context.Context, req *pb.StatSummaryRequest
 		}
 	}
 
+	if err := s.checkTimeWindow(ctx, req.TimeWindow); err != nil {
+		return statSummaryError(req, err.Error()), nil
+	}
+
 	statTables := make([]*pb.StatTable, 0)
 
 	var resourcesToQuery []string
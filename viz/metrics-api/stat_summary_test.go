@@ -3,14 +3,17 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/linkerd/linkerd2/controller/k8s"
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
 	"github.com/linkerd/linkerd2/pkg/prometheus"
 	pb "github.com/linkerd/linkerd2/viz/metrics-api/gen/viz"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 )
 
@@ -1597,6 +1600,91 @@ status:
 		}
 	})
 
+	t.Run("Validates the time window against the Prometheus retention", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI()
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+
+		testCases := []struct {
+			timeWindow string
+			flags      promv1.FlagsResult
+			valid      bool
+		}{
+			{"1h", promv1.FlagsResult{"storage.tsdb.retention.time": "6h"}, true},
+			{"6h", promv1.FlagsResult{"storage.tsdb.retention.time": "6h"}, true},
+			{"24h", promv1.FlagsResult{"storage.tsdb.retention.time": "6h"}, false},
+			{"5400s", promv1.FlagsResult{"storage.tsdb.retention.time": "1d"}, true},
+			{"24h", promv1.FlagsResult{"storage.tsdb.retention.time": "0s"}, true},
+			{"24h", promv1.FlagsResult{}, true},
+			{"1.5h", promv1.FlagsResult{}, false},
+		}
+
+		for _, tc := range testCases {
+			tc := tc // pin
+			t.Run(fmt.Sprintf("%s with retention %q", tc.timeWindow, tc.flags["storage.tsdb.retention.time"]), func(t *testing.T) {
+				fakeGrpcServer := newGrpcServer(
+					&prometheus.MockProm{Res: model.Vector{}, FlagsRes: tc.flags},
+					k8sAPI,
+					"linkerd",
+					"mycluster.local",
+					[]string{},
+				)
+
+				rsp, err := fakeGrpcServer.StatSummary(context.TODO(), &pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Type: pkgK8s.Pod,
+						},
+					},
+					TimeWindow: tc.timeWindow,
+				})
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				if tc.valid && rsp.GetError() != nil {
+					t.Fatalf("Did not expect validation error on StatSummaryResponse, got %v", rsp.GetError())
+				}
+				if !tc.valid && rsp.GetError() == nil {
+					t.Fatalf("Expected validation error on StatSummaryResponse, got %v", rsp)
+				}
+			})
+		}
+
+		t.Run("caches the retention", func(t *testing.T) {
+			mockProm := &prometheus.MockProm{
+				Res:      model.Vector{},
+				FlagsRes: promv1.FlagsResult{"storage.tsdb.retention.time": "6h"},
+			}
+			fakeGrpcServer := newGrpcServer(mockProm, k8sAPI, "linkerd", "mycluster.local", []string{})
+
+			for i := 0; i < 3; i++ {
+				if _, err := fakeGrpcServer.StatSummary(context.TODO(), &pb.StatSummaryRequest{
+					Selector: &pb.ResourceSelection{
+						Resource: &pb.Resource{
+							Type: pkgK8s.Pod,
+						},
+					},
+					TimeWindow: "1h",
+				}); err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+			}
+			if mockProm.FlagsCalls != 1 {
+				t.Fatalf("Expected the Prometheus flags to be fetched once, got %d", mockProm.FlagsCalls)
+			}
+
+			// the retention is fetched again once it expires
+			fakeGrpcServer.retention.fetchedAt = time.Now().Add(-promRetentionTTL)
+			if err := fakeGrpcServer.checkTimeWindow(context.TODO(), "1h"); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if mockProm.FlagsCalls != 2 {
+				t.Fatalf("Expected the Prometheus flags to be fetched again, got %d calls", mockProm.FlagsCalls)
+			}
+		})
+	})
+
 	t.Run("Return empty stats summary response", func(t *testing.T) {
 		t.Run("when pod phase is succeeded or failed", func(t *testing.T) {
 			expectations := []statSumExpected{
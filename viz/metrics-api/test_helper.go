@@ -3,8 +3,11 @@ package api
 import (
 	"context"
 	"fmt"
+	"net/http/httptest"
 	"reflect"
 	"sort"
+	"sync"
+	"time"
 
 	"github.com/linkerd/linkerd2/controller/k8s"
 	"github.com/linkerd/linkerd2/pkg/prometheus"
@@ -60,6 +63,93 @@ func (c *MockAPIClient) SelfCheck(ctx context.Context, in *pb.SelfCheckRequest,
 	return c.SelfCheckResponseToReturn, c.ErrorToReturn
 }
 
+// MockAPIServer satisfies the metrics-api gRPC server interface, returning
+// canned responses. It can be served over HTTP with NewMockAPIHTTPServer, to
+// test clients end to end without a cluster.
+type MockAPIServer struct {
+	pb.UnimplementedApiServer
+
+	ErrorToReturn                error
+	ListPodsResponseToReturn     *pb.ListPodsResponse
+	ListServicesResponseToReturn *pb.ListServicesResponse
+	StatSummaryResponseToReturn  *pb.StatSummaryResponse
+	GatewaysResponseToReturn     *pb.GatewaysResponse
+	TopRoutesResponseToReturn    *pb.TopRoutesResponse
+	EdgesResponseToReturn        *pb.EdgesResponse
+	SelfCheckResponseToReturn    *pb.SelfCheckResponse
+
+	// Delay is how long each call waits before returning, unless its context
+	// is done first, to simulate a slow server
+	Delay time.Duration
+
+	mu    sync.Mutex
+	calls int
+}
+
+// Calls returns the number of calls the server has received.
+func (s *MockAPIServer) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func (s *MockAPIServer) call(ctx context.Context) error {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+
+	if s.Delay > 0 {
+		select {
+		case <-time.After(s.Delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return s.ErrorToReturn
+}
+
+// StatSummary provides a mock of a metrics-api method.
+func (s *MockAPIServer) StatSummary(ctx context.Context, in *pb.StatSummaryRequest) (*pb.StatSummaryResponse, error) {
+	return s.StatSummaryResponseToReturn, s.call(ctx)
+}
+
+// Gateways provides a mock of a metrics-api method.
+func (s *MockAPIServer) Gateways(ctx context.Context, in *pb.GatewaysRequest) (*pb.GatewaysResponse, error) {
+	return s.GatewaysResponseToReturn, s.call(ctx)
+}
+
+// TopRoutes provides a mock of a metrics-api method.
+func (s *MockAPIServer) TopRoutes(ctx context.Context, in *pb.TopRoutesRequest) (*pb.TopRoutesResponse, error) {
+	return s.TopRoutesResponseToReturn, s.call(ctx)
+}
+
+// Edges provides a mock of a metrics-api method.
+func (s *MockAPIServer) Edges(ctx context.Context, in *pb.EdgesRequest) (*pb.EdgesResponse, error) {
+	return s.EdgesResponseToReturn, s.call(ctx)
+}
+
+// ListPods provides a mock of a metrics-api method.
+func (s *MockAPIServer) ListPods(ctx context.Context, in *pb.ListPodsRequest) (*pb.ListPodsResponse, error) {
+	return s.ListPodsResponseToReturn, s.call(ctx)
+}
+
+// ListServices provides a mock of a metrics-api method.
+func (s *MockAPIServer) ListServices(ctx context.Context, in *pb.ListServicesRequest) (*pb.ListServicesResponse, error) {
+	return s.ListServicesResponseToReturn, s.call(ctx)
+}
+
+// SelfCheck provides a mock of a metrics-api method.
+func (s *MockAPIServer) SelfCheck(ctx context.Context, in *pb.SelfCheckRequest) (*pb.SelfCheckResponse, error) {
+	return s.SelfCheckResponseToReturn, s.call(ctx)
+}
+
+// NewMockAPIHTTPServer starts an HTTP server serving the metrics-api with the
+// given server, the same way the real metrics-api does. The caller must close
+// the returned server.
+func NewMockAPIHTTPServer(server Server) *httptest.Server {
+	return httptest.NewServer(&handler{grpcServer: server})
+}
+
 // PodCounts is a test helper struct that is used for representing data in a
 // StatTable.PodGroup.Row.
 type PodCounts struct {
@@ -50,6 +50,10 @@ func (s *grpcServer) TopRoutes(ctx context.Context, req *pb.TopRoutesRequest) (*
 		return errRsp, nil
 	}
 
+	if err := s.checkTimeWindow(ctx, req.TimeWindow); err != nil {
+		return topRoutesError(req, err.Error()), nil
+	}
+
 	// TopRoutes will return one table for each resource object requested.
 	tables := make([]resourceTable, 0)
 	targetResource := req.GetSelector().GetResource()
@@ -7,8 +7,11 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/linkerd/linkerd2/controller/k8s"
 	pkgK8s "github.com/linkerd/linkerd2/pkg/k8s"
+	"github.com/linkerd/linkerd2/pkg/prometheus"
 	pb "github.com/linkerd/linkerd2/viz/metrics-api/gen/viz"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 )
 
@@ -499,4 +502,43 @@ func TestTopRoutes(t *testing.T) {
 
 		testTopRoutes(t, expectations)
 	})
+	t.Run("Validates the time window against the Prometheus retention", func(t *testing.T) {
+		k8sAPI, err := k8s.NewFakeAPI(booksConfig...)
+		if err != nil {
+			t.Fatalf("NewFakeAPI returned an error: %s", err)
+		}
+		k8sAPI.Sync(nil)
+
+		fakeGrpcServer := newGrpcServer(
+			&prometheus.MockProm{
+				Res:      routesMetric([]string{"/a"}),
+				FlagsRes: promv1.FlagsResult{"storage.tsdb.retention.time": "6h"},
+			},
+			k8sAPI,
+			"linkerd",
+			"cluster.local",
+			[]string{},
+		)
+
+		rsp, err := fakeGrpcServer.TopRoutes(context.TODO(), &pb.TopRoutesRequest{
+			Selector: &pb.ResourceSelection{
+				Resource: &pb.Resource{
+					Namespace: "default",
+					Type:      pkgK8s.Deployment,
+					Name:      "books",
+				},
+			},
+			TimeWindow: "24h",
+			Outbound: &pb.TopRoutesRequest_None{
+				None: &pb.Empty{},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		expected := "time window 24h exceeds the Prometheus retention period of 6h"
+		if rsp.GetError().GetError() != expected {
+			t.Fatalf("Expected error %q, got %v", expected, rsp)
+		}
+	})
 }
@@ -2,6 +2,8 @@ package util
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/linkerd/linkerd2/pkg/k8s"
@@ -12,6 +14,10 @@ import (
 var (
 	defaultMetricTimeWindow    = "1m"
 	metricTimeWindowLowerBound = time.Second * 15 //the window value needs to equal or larger than that
+
+	// time windows made of a single integer and unit are understood by both Go
+	// and Prometheus; anything else is converted to seconds
+	promTimeWindowRegex = regexp.MustCompile(`^[0-9]+[smh]$`)
 )
 
 // StatsBaseRequestParams contains parameters that are used to build requests
@@ -79,7 +85,14 @@ func BuildStatSummaryRequest(p StatsSummaryRequestParams) (*pb.StatSummaryReques
 			return nil, errors.New("metrics time window needs to be at least 15s")
 		}
 
+		if w%time.Second != 0 {
+			return nil, errors.New("metrics time window needs to be a whole number of seconds")
+		}
+
 		window = p.TimeWindow
+		if !promTimeWindowRegex.MatchString(window) {
+			window = fmt.Sprintf("%ds", w/time.Second)
+		}
 	}
 
 	if p.AllNamespaces && p.ResourceName != "" {
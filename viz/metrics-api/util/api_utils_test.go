@@ -63,10 +63,39 @@ func TestBuildStatSummaryRequest(t *testing.T) {
 		}
 	})
 
+	t.Run("Converts time windows Prometheus can't parse to seconds", func(t *testing.T) {
+		expectations := map[string]string{
+			"24h":   "24h",
+			"1.5h":  "5400s",
+			"1h30m": "5400s",
+			"90.0s": "90s",
+		}
+
+		for timeWindow, expected := range expectations {
+			statSummaryRequest, err := BuildStatSummaryRequest(
+				StatsSummaryRequestParams{
+					StatsBaseRequestParams: StatsBaseRequestParams{
+						TimeWindow:   timeWindow,
+						ResourceType: k8s.Deployment,
+					},
+				},
+			)
+			if err != nil {
+				t.Fatalf("Unexpected error from BuildStatSummaryRequest [%s => %s]", timeWindow, err)
+			}
+			if statSummaryRequest.TimeWindow != expected {
+				t.Fatalf("Unexpected TimeWindow from BuildStatSummaryRequest [%s => %s], expected %s", timeWindow, statSummaryRequest.TimeWindow, expected)
+			}
+		}
+	})
+
 	t.Run("Rejects invalid time windows", func(t *testing.T) {
 		expectations := map[string]string{
-			"1": "time: missing unit in duration \"1\"",
-			"s": "time: invalid duration \"s\"",
+			"1":      "time: missing unit in duration \"1\"",
+			"s":      "time: invalid duration \"s\"",
+			"10s":    "metrics time window needs to be at least 15s",
+			"15.5s":  "metrics time window needs to be a whole number of seconds",
+			"1m10ms": "metrics time window needs to be a whole number of seconds",
 		}
 
 		for timeWindow, msg := range expectations {
@@ -38,6 +38,34 @@ const (
 	linkerdTapAPIServiceName = "v1alpha1.tap.linkerd.io"
 )
 
+// SelfCheckError is returned by the viz API check when any of the metrics-api
+// subsystems reports a failure. It keeps each subsystem's result alongside
+// the category of the check that ran it, so that programmatic consumers
+// don't need to parse the error message.
+type SelfCheckError struct {
+	Category healthcheck.CategoryID
+	Results  []*pb.CheckResult
+}
+
+// Error satisfies the error interface for SelfCheckError. The output is
+// intended for `linkerd viz check`.
+func (e *SelfCheckError) Error() string {
+	errs := []string{}
+	for _, res := range e.Results {
+		errs = append(errs, res.GetFriendlyMessageToUser())
+	}
+	return strings.Join(errs, "\n    ")
+}
+
+// SubsystemResults groups the failed results by subsystem name
+func (e *SelfCheckError) SubsystemResults() map[string][]*pb.CheckResult {
+	grouped := make(map[string][]*pb.CheckResult)
+	for _, res := range e.Results {
+		grouped[res.GetSubsystemName()] = append(grouped[res.GetSubsystemName()], res)
+	}
+	return grouped
+}
+
 // HealthChecker wraps Linkerd's main healthchecker, adding extra fields for Viz
 type HealthChecker struct {
 	*healthcheck.HealthChecker
@@ -251,7 +279,10 @@ func (hc *HealthChecker) VizCategory() *healthcheck.Category {
 			WithHintAnchor("l5d-viz-existence-client").
 			Fatal().
 			WithCheck(func(ctx context.Context) (err error) {
-				hc.vizAPIClient, err = client.NewExternalClient(ctx, hc.vizNamespace, hc.KubeAPIClient())
+				hc.vizAPIClient, err = client.NewExternalClientWithOptions(ctx, hc.vizNamespace, hc.KubeAPIClient(), client.ExternalClientOptions{
+					Timeout: hc.APITimeout,
+					Trace:   hc.TraceAPIRequests,
+				})
 				return
 			}),
 		*healthcheck.NewChecker("viz extension self-check").
@@ -271,18 +302,20 @@ func (hc *HealthChecker) VizCategory() *healthcheck.Category {
 					return errors.New("No results returned")
 				}
 
-				errs := []string{}
+				failed := []*pb.CheckResult{}
 				for _, res := range results.GetResults() {
 					if res.GetStatus() != pb.CheckStatus_OK {
-						errs = append(errs, res.GetFriendlyMessageToUser())
+						failed = append(failed, res)
 					}
 				}
-				if len(errs) == 0 {
+				if len(failed) == 0 {
 					return nil
 				}
 
-				errsStr := strings.Join(errs, "\n    ")
-				return errors.New(errsStr)
+				return &SelfCheckError{
+					Category: LinkerdVizExtensionCheck,
+					Results:  failed,
+				}
 			}),
 	}, true)
 }
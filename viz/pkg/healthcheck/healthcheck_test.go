@@ -0,0 +1,38 @@
+package healthcheck
+
+import (
+	"reflect"
+	"testing"
+
+	pb "github.com/linkerd/linkerd2/viz/metrics-api/gen/viz"
+)
+
+func TestSelfCheckError(t *testing.T) {
+	k8sResult := &pb.CheckResult{
+		SubsystemName:         "kubernetes",
+		Status:                pb.CheckStatus_FAIL,
+		FriendlyMessageToUser: "cannot reach kubernetes",
+	}
+	promResult := &pb.CheckResult{
+		SubsystemName:         "prometheus",
+		Status:                pb.CheckStatus_ERROR,
+		FriendlyMessageToUser: "cannot reach prometheus",
+	}
+	err := &SelfCheckError{
+		Category: LinkerdVizExtensionCheck,
+		Results:  []*pb.CheckResult{k8sResult, promResult},
+	}
+
+	expectedMsg := "cannot reach kubernetes\n    cannot reach prometheus"
+	if err.Error() != expectedMsg {
+		t.Fatalf("Expected error message %q, got %q", expectedMsg, err.Error())
+	}
+
+	expectedGroups := map[string][]*pb.CheckResult{
+		"kubernetes": {k8sResult},
+		"prometheus": {promResult},
+	}
+	if !reflect.DeepEqual(err.SubsystemResults(), expectedGroups) {
+		t.Fatalf("Expected subsystem results %v, got %v", expectedGroups, err.SubsystemResults())
+	}
+}
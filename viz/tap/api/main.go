@@ -25,6 +25,7 @@ func Main(args []string) {
 	apiNamespace := cmd.String("api-namespace", "linkerd", "namespace in which Linkerd is installed")
 	tapPort := cmd.Uint("tap-port", 4190, "proxy tap port to connect to")
 	disableCommonNames := cmd.Bool("disable-common-names", false, "disable checks for Common Names (for development)")
+	apiServerName := cmd.String("apiserver-name", "", "DNS name of the tap service (e.g. tap.linkerd-viz.svc), checked against the serving certificate at startup; no check is performed if empty")
 	trustDomain := cmd.String("identity-trust-domain", defaultDomain, "configures the name suffix used for identities")
 	traceCollector := flags.AddTraceFlags(cmd)
 	flags.ConfigureAndParse(cmd, args)
@@ -57,7 +58,7 @@ func Main(args []string) {
 		}
 	}
 	grpcTapServer := NewGrpcTapServer(*tapPort, *apiNamespace, *trustDomain, k8sAPI)
-	apiServer, err := NewServer(ctx, *apiServerAddr, k8sAPI, grpcTapServer, *disableCommonNames)
+	apiServer, err := NewServer(ctx, *apiServerAddr, k8sAPI, grpcTapServer, *disableCommonNames, *apiServerName)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
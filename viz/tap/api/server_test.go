@@ -177,6 +177,20 @@ func TestIsSubjectAlternateName(t *testing.T) {
 	}
 }
 
+func TestValidateServingCert(t *testing.T) {
+	cert := testCertificate()
+
+	if err := validateServingCert(&cert, "linkerd.io"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := "serving certificate is not valid for tap.linkerd-viz.svc, only for [localhost linkerd.io]: check the tap certificate's Subject Alternate Names"
+	err := validateServingCert(&cert, "tap.linkerd-viz.svc")
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Expected error %q, got %v", expected, err)
+	}
+}
+
 func testCertificate() x509.Certificate {
 	uri, _ := url.Parse("http://localhost/api/test")
 	cert := x509.Certificate{
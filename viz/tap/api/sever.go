@@ -38,6 +38,7 @@ func NewServer(
 	k8sAPI *k8s.API,
 	grpcTapServer pb.TapServer,
 	disableCommonNames bool,
+	serverName string,
 ) (*Server, error) {
 	updateEvent := make(chan struct{})
 	errEvent := make(chan error)
@@ -64,6 +65,10 @@ func NewServer(
 		"addr":      addr,
 	})
 
+	if len(allowedNames) == 0 {
+		log.Warn("no allowed client names configured: accepting requests from any client certificate signed by the request header CA")
+	}
+
 	clientCertPool := x509.NewCertPool()
 	clientCertPool.AppendCertsFromPEM([]byte(clientCAPem))
 
@@ -104,6 +109,16 @@ func NewServer(
 		return nil, fmt.Errorf("Failed to initialized certificate: %s", err)
 	}
 
+	if serverName != "" {
+		cert, err := x509.ParseCertificate(s.certValue.Load().(*tls.Certificate).Certificate[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %s", err)
+		}
+		if err := validateServingCert(cert, serverName); err != nil {
+			return nil, err
+		}
+	}
+
 	go watcher.ProcessEvents(log, s.certValue, updateEvent, errEvent)
 
 	return s, nil
@@ -215,6 +230,16 @@ func deserializeStrings(in string) ([]string, error) {
 	return ret, nil
 }
 
+// validateServingCert checks that the serving certificate is valid for name,
+// the service DNS name the Kubernetes API server uses to reach the tap
+// APIService. Otherwise requests would fail at handshake time.
+func validateServingCert(cert *x509.Certificate, name string) error {
+	if err := cert.VerifyHostname(name); err != nil {
+		return fmt.Errorf("serving certificate is not valid for %s, only for %v: check the tap certificate's Subject Alternate Names", name, cert.DNSNames)
+	}
+	return nil
+}
+
 // isSubjectAlternateName checks all applicable fields within the certificate for a match to the provided name.
 // See https://tools.ietf.org/html/rfc5280#section-4.2.1.6 for information about Subject Alternate Name.
 func isSubjectAlternateName(cert *x509.Certificate, name string) bool {
@@ -0,0 +1,35 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	tapPb "github.com/linkerd/linkerd2/viz/tap/gen/tap"
+)
+
+func TestBuildTapByResourceRequestHTTPMatches(t *testing.T) {
+	req, err := BuildTapByResourceRequest(TapRequestParams{
+		Resource:  "deploy/checkout",
+		Namespace: "shop",
+		Method:    "POST",
+		Path:      "/checkout",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := []*tapPb.TapByResourceRequest_Match_Http{
+		{Match: &tapPb.TapByResourceRequest_Match_Http_Method{Method: "POST"}},
+		{Match: &tapPb.TapByResourceRequest_Match_Http_Path{Path: "/checkout"}},
+	}
+
+	matches := req.GetMatch().GetAll().GetMatches()
+	if len(matches) != len(expected) {
+		t.Fatalf("Expected %d matches, got %d: %v", len(expected), len(matches), matches)
+	}
+	for i, match := range matches {
+		if !proto.Equal(match.GetHttp(), expected[i]) {
+			t.Fatalf("Expected match %d to be %v, got %v", i, expected[i], match.GetHttp())
+		}
+	}
+}